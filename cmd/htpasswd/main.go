@@ -0,0 +1,48 @@
+// Command htpasswd prints a Basic Auth users file entry with a bcrypt hash.
+//
+//	go run ./cmd/htpasswd -user admin -scopes users:read,users:write >> .htpasswd
+//
+// The password is read from stdin.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+func main() {
+	user := flag.String("user", "", "username")
+	scopes := flag.String("scopes", "", "comma separated scopes, * for all routes")
+	cost := flag.Int("cost", bcrypt.DefaultCost, "bcrypt cost")
+	flag.Parse()
+
+	if *user == "" || strings.Contains(*user, ":") {
+		fmt.Fprintln(os.Stderr, "usage: htpasswd -user NAME [-scopes a,b] < password")
+		os.Exit(2)
+	}
+
+	fmt.Fprint(os.Stderr, "Password: ")
+	password, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && password == "" {
+		fmt.Fprintln(os.Stderr, "error: no password given")
+		os.Exit(1)
+	}
+	password = strings.TrimRight(password, "\r\n")
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), *cost)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+
+	line := *user + ":" + string(hash)
+	if *scopes != "" {
+		line += ":" + *scopes
+	}
+	fmt.Println(line)
+}
@@ -0,0 +1,151 @@
+// Command localecheck compares every locale bundle against the default
+// language and reports missing, extra and unused keys and placeholder
+// mismatches. Run it from the repository root:
+//
+//	go run ./cmd/localecheck
+//
+// Keys referenced by the code are the TYPE_* response types and string
+// literals passed to config.Error, config.ErrorWithParams, locale.T,
+// locale.Get and locale.Lookup. Bundles are the embedded ones, those the
+// modules register through modules.RegisterLocales, as in the server, and,
+// with -dir, an override directory.
+// It exits with status 1 when it finds a problem.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/addixit1/fiber-boilerplate/internal/lib/locale"
+	"github.com/addixit1/fiber-boilerplate/internal/modules"
+)
+
+// keyArgs maps package.function to the position of its locale key argument
+var keyArgs = map[string]int{
+	"config.Error":           0,
+	"config.ErrorWithParams": 0,
+	"locale.T":               1,
+	"locale.Get":             1,
+	"locale.Lookup":          1,
+}
+
+func main() {
+	src := flag.String("src", "internal", "directory of Go sources to scan for referenced keys")
+	dir := flag.String("dir", "", "locale override directory, as LOCALE_DIR")
+	unused := flag.Bool("unused", true, "report keys of the default language that the code never references")
+	flag.Parse()
+
+	modules.RegisterLocales()
+	locale.SetOverrideDir(*dir)
+	if err := locale.Load(); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+
+	var referenced []string
+	if *unused {
+		refs, err := referencedKeys(*src)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			os.Exit(1)
+		}
+		referenced = refs
+	}
+
+	problems := 0
+	for _, report := range locale.Check(referenced...) {
+		for _, line := range report.Problems() {
+			fmt.Println(line)
+			problems++
+		}
+	}
+
+	if problems > 0 {
+		fmt.Fprintf(os.Stderr, "%d problems\n", problems)
+		os.Exit(1)
+	}
+	fmt.Println("locales OK")
+}
+
+// referencedKeys collects the locale keys used by the Go files under dir.
+// Keys built by concatenation, e.g. "validation." + rule, are returned as
+// their literal prefix.
+func referencedKeys(dir string) ([]string, error) {
+	seen := map[string]bool{}
+	fset := token.NewFileSet()
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return err
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch node := n.(type) {
+			case *ast.ValueSpec:
+				for i, name := range node.Names {
+					if strings.HasPrefix(name.Name, "TYPE_") && i < len(node.Values) {
+						if key, ok := literalKey(node.Values[i]); ok {
+							seen[key] = true
+						}
+					}
+				}
+			case *ast.CallExpr:
+				sel, ok := node.Fun.(*ast.SelectorExpr)
+				if !ok {
+					return true
+				}
+				pkg, ok := sel.X.(*ast.Ident)
+				if !ok {
+					return true
+				}
+				pos, ok := keyArgs[pkg.Name+"."+sel.Sel.Name]
+				if !ok || pos >= len(node.Args) {
+					return true
+				}
+				if key, ok := literalKey(node.Args[pos]); ok {
+					seen[key] = true
+				}
+			}
+			return true
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// literalKey returns a string literal, or the literal left operand of a concatenation
+func literalKey(expr ast.Expr) (string, bool) {
+	switch e := expr.(type) {
+	case *ast.BasicLit:
+		if e.Kind != token.STRING {
+			return "", false
+		}
+		value, err := strconv.Unquote(e.Value)
+		return value, err == nil
+	case *ast.BinaryExpr:
+		if e.Op == token.ADD {
+			return literalKey(e.X)
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,65 @@
+// Command lockout lists and clears brute-force lockouts straight in Redis,
+// for when no admin can sign in to DELETE /lockouts:
+//
+//	go run ./cmd/lockout list
+//	go run ./cmd/lockout clear basicauth account ops@203.0.113.7
+//	go run ./cmd/lockout clear basicauth ip 203.0.113.7
+//
+// It reads the same configuration as the server.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/addixit1/fiber-boilerplate/internal/config"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/bruteforce"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/redis"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	if err := config.Load(); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+	redis.Init()
+
+	var err error
+	switch os.Args[1] {
+	case "list":
+		err = list()
+	case "clear":
+		if len(os.Args) != 5 {
+			usage()
+		}
+		err = bruteforce.Clear(os.Args[2], bruteforce.Kind(os.Args[3]), os.Args[4])
+	default:
+		usage()
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: lockout list | clear SCOPE account|ip SUBJECT")
+	os.Exit(2)
+}
+
+func list() error {
+	lockouts, err := bruteforce.List(context.Background())
+	if err != nil {
+		return err
+	}
+	for _, l := range lockouts {
+		fmt.Printf("%s\t%s\t%s\t%ds\n", l.Scope, l.Kind, l.Subject, l.RetryAfter)
+	}
+	return nil
+}
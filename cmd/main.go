@@ -4,6 +4,7 @@ import (
 	"log"
 
 	"github.com/addixit1/fiber-boilerplate/internal/app"
+	"github.com/addixit1/fiber-boilerplate/internal/config"
 )
 
 // @title  Fiber Boilerplate
@@ -22,17 +23,20 @@ import (
 // @securityDefinitions.apikey BearerAuth
 // @in header
 // @name Authorization
+// @securityDefinitions.apikey APIKeyAuth
+// @in header
+// @name X-API-Key
 
 // 📋 Global Headers
 // @Param platform header string false "Device OS: 1-Android, 2-iOS, 3-WEB" Enums(1, 2, 3) default(1)
 // @Param timezone header string false "Time zone" default(Asia/Kolkata)
-// @Param offset header number false "Time zone offset" default(0)
+// @Param offset header number false "Time zone offset in minutes east of UTC, used when timezone is not a valid IANA name" default(0)
 // @Param accept-language header string false "Language preference: en, hi" Enums(en, hi) default(en)
 // @Param appversion header string false "App version" default(v1)
 // @Param routeversion header string false "Route version" default(v1)
 
 func main() {
 	fiberApp := app.New()
-	log.Fatal(fiberApp.Listen(":3010"))
+	log.Fatal(fiberApp.Listen(config.Config.Addr()))
 
 }
@@ -0,0 +1,111 @@
+// Command secrets manages the encrypted secrets file read by the "file" secret provider.
+//
+//	go run ./cmd/secrets keygen
+//	SECRETS_KEY=... go run ./cmd/secrets encrypt -in secrets.json -out secrets.enc
+//	SECRETS_KEY=... go run ./cmd/secrets decrypt -in secrets.enc
+//
+// The plain JSON input maps environment variable names to values,
+// e.g. {"JWT_SECRET": "...", "MONGO_URI": "..."}.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/addixit1/fiber-boilerplate/internal/config"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "keygen":
+		err = keygen()
+	case "encrypt":
+		err = encrypt(os.Args[2:])
+	case "decrypt":
+		err = decrypt(os.Args[2:])
+	default:
+		usage()
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: secrets keygen | encrypt -in secrets.json -out secrets.enc | decrypt -in secrets.enc")
+	os.Exit(2)
+}
+
+func keygen() error {
+	key, err := config.GenerateSecretsKey()
+	if err != nil {
+		return err
+	}
+	fmt.Println(key)
+	return nil
+}
+
+func encrypt(args []string) error {
+	fs := flag.NewFlagSet("encrypt", flag.ExitOnError)
+	in := fs.String("in", "secrets.json", "plain JSON secrets")
+	out := fs.String("out", "secrets.enc", "encrypted output file")
+	_ = fs.Parse(args)
+
+	key, err := config.DecodeSecretsKey(os.Getenv("SECRETS_KEY"))
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(*in)
+	if err != nil {
+		return err
+	}
+	secrets := map[string]string{}
+	if err := json.Unmarshal(data, &secrets); err != nil {
+		return fmt.Errorf("%s: %w", *in, err)
+	}
+
+	sealed, err := config.EncryptSecrets(key, secrets)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(*out, sealed, 0o600); err != nil {
+		return err
+	}
+
+	fmt.Printf("wrote %d secrets to %s\n", len(secrets), *out)
+	return nil
+}
+
+func decrypt(args []string) error {
+	fs := flag.NewFlagSet("decrypt", flag.ExitOnError)
+	in := fs.String("in", "secrets.enc", "encrypted secrets file")
+	_ = fs.Parse(args)
+
+	key, err := config.DecodeSecretsKey(os.Getenv("SECRETS_KEY"))
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(*in)
+	if err != nil {
+		return err
+	}
+	secrets, err := config.DecryptSecrets(key, data)
+	if err != nil {
+		return err
+	}
+
+	out, _ := json.MarshalIndent(secrets, "", "  ")
+	fmt.Println(string(out))
+	return nil
+}
@@ -19,14 +19,1077 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
+        "/api-keys": {
+            "get": {
+                "security": [
+                    {
+                        "basicAuth": []
+                    }
+                ],
+                "description": "Get all API keys, optionally filtered by owner",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Keys"
+                ],
+                "summary": "List API keys",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by owner",
+                        "name": "owner",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "hi"
+                        ],
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language: en, hi",
+                        "name": "accept-language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "basicAuth": []
+                    }
+                ],
+                "description": "Create an API key. The secret is only returned in this response.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Keys"
+                ],
+                "summary": "Create an API key",
+                "parameters": [
+                    {
+                        "description": "Create API key",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_modules_apikey_v1.CreateAPIKeyDTO"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "hi"
+                        ],
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language: en, hi",
+                        "name": "accept-language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api-keys/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "basicAuth": []
+                    }
+                ],
+                "description": "Get an API key with its usage over the last 7 days",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Keys"
+                ],
+                "summary": "Get an API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "hi"
+                        ],
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language: en, hi",
+                        "name": "accept-language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "basicAuth": []
+                    }
+                ],
+                "description": "Revoke an API key. Revoked keys are kept for auditing.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Keys"
+                ],
+                "summary": "Revoke an API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "hi"
+                        ],
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language: en, hi",
+                        "name": "accept-language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api-keys/{id}/rotate": {
+            "post": {
+                "security": [
+                    {
+                        "basicAuth": []
+                    }
+                ],
+                "description": "Issue a new secret for an API key. The old secret stops working immediately. Revoked keys cannot be rotated.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "API Keys"
+                ],
+                "summary": "Rotate an API key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "hi"
+                        ],
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language: en, hi",
+                        "name": "accept-language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/app-version": {
+            "get": {
+                "description": "Compare the caller's appversion header with the minimum and latest versions of its platform",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "App Versions"
+                ],
+                "summary": "Check the app version",
+                "parameters": [
+                    {
+                        "enum": [
+                            "1",
+                            "2",
+                            "3"
+                        ],
+                        "type": "string",
+                        "default": "1",
+                        "description": "Device OS: 1-Android, 2-iOS, 3-WEB",
+                        "name": "platform",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "default": "1.0.0",
+                        "description": "App version",
+                        "name": "appversion",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "hi"
+                        ],
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language: en, hi",
+                        "name": "accept-language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    },
+                    "426": {
+                        "description": "Upgrade Required",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/app-versions": {
+            "get": {
+                "security": [
+                    {
+                        "basicAuth": []
+                    }
+                ],
+                "description": "Get the minimum and latest app versions of every platform",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "App Versions"
+                ],
+                "summary": "List app version policies",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en",
+                            "hi"
+                        ],
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language: en, hi",
+                        "name": "accept-language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/app-versions/{platform}": {
+            "put": {
+                "security": [
+                    {
+                        "basicAuth": []
+                    }
+                ],
+                "description": "Set the minimum and latest app versions of a platform. Clients below the minimum get 426 FORCE_UPDATE.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "App Versions"
+                ],
+                "summary": "Set an app version policy",
+                "parameters": [
+                    {
+                        "enum": [
+                            1,
+                            2
+                        ],
+                        "type": "integer",
+                        "description": "Platform: 1-Android, 2-iOS",
+                        "name": "platform",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Version policy",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_modules_appversion_v1.SetAppVersionDTO"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "hi"
+                        ],
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language: en, hi",
+                        "name": "accept-language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/audit/{collection}/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "basicAuth": []
+                    }
+                ],
+                "description": "Get the audit trail of a document, newest change first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Audit"
+                ],
+                "summary": "Document history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "example": "users",
+                        "description": "Collection name",
+                        "name": "collection",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Document ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 1,
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "hi"
+                        ],
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language: en, hi",
+                        "name": "accept-language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.ListResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lockouts": {
+            "get": {
+                "security": [
+                    {
+                        "basicAuth": []
+                    }
+                ],
+                "description": "Get every account and IP currently locked out after failed logins",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Lockouts"
+                ],
+                "summary": "List lockouts",
+                "parameters": [
+                    {
+                        "enum": [
+                            "en",
+                            "hi"
+                        ],
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language: en, hi",
+                        "name": "accept-language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/lockouts/{scope}/{kind}/{subject}": {
+            "delete": {
+                "security": [
+                    {
+                        "basicAuth": []
+                    }
+                ],
+                "description": "Unlock an account or IP and reset its failed attempts",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Lockouts"
+                ],
+                "summary": "Clear a lockout",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Login flow, e.g. basicauth",
+                        "name": "scope",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "account",
+                            "ip"
+                        ],
+                        "type": "string",
+                        "description": "account or ip",
+                        "name": "kind",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Username@IP, as listed, or IP address",
+                        "name": "subject",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "hi"
+                        ],
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language: en, hi",
+                        "name": "accept-language",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/users": {
             "get": {
                 "security": [
                     {
-                        "BearerAuth": []
+                        "basicAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Get all users with search",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "List users",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search by name or email",
+                        "name": "search",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter: filter[field][op]=value, op is eq, ne, gt, gte, lt, lte, in, nin, contains, prefix or exists",
+                        "name": "filter[name][contains]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "-created_at",
+                        "description": "Sort fields, - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "example": "name,email",
+                        "description": "Fields to return",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number; enables page/limit pagination",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Page size; without page enables cursor pagination",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "nextCursor or prevCursor of a previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "1",
+                            "2",
+                            "3"
+                        ],
+                        "type": "string",
+                        "default": "1",
+                        "description": "Device OS: 1-Android, 2-iOS, 3-WEB",
+                        "name": "platform",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "default": "Asia/Kolkata",
+                        "description": "Time zone",
+                        "name": "timezone",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Time zone offset in minutes east of UTC, used when timezone is not a valid IANA name",
+                        "name": "offset",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "hi"
+                        ],
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language: en, hi",
+                        "name": "accept-language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "default": "v1",
+                        "description": "App version",
+                        "name": "appversion",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "default": "v1",
+                        "description": "Route version",
+                        "name": "routeversion",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "basicAuth": []
+                    }
+                ],
+                "description": "Create a new user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Create a new user",
+                "parameters": [
+                    {
+                        "description": "Create user",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/internal_modules_user_v1.CreateUserDTO"
+                        }
+                    },
+                    {
+                        "enum": [
+                            "1",
+                            "2",
+                            "3"
+                        ],
+                        "type": "string",
+                        "default": "1",
+                        "description": "Device OS: 1-Android, 2-iOS, 3-WEB",
+                        "name": "platform",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "default": "Asia/Kolkata",
+                        "description": "Time zone",
+                        "name": "timezone",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Time zone offset in minutes east of UTC, used when timezone is not a valid IANA name",
+                        "name": "offset",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "hi"
+                        ],
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language: en, hi",
+                        "name": "accept-language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "default": "v1",
+                        "description": "App version",
+                        "name": "appversion",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "default": "v1",
+                        "description": "Route version",
+                        "name": "routeversion",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "basicAuth": []
+                    },
+                    {
+                        "APIKeyAuth": []
+                    }
+                ],
+                "description": "Get a user by ID; the ETag header carries its version for If-Match",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Get a user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "1",
+                            "2",
+                            "3"
+                        ],
+                        "type": "string",
+                        "default": "1",
+                        "description": "Device OS: 1-Android, 2-iOS, 3-WEB",
+                        "name": "platform",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "default": "Asia/Kolkata",
+                        "description": "Time zone",
+                        "name": "timezone",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Time zone offset in minutes east of UTC, used when timezone is not a valid IANA name",
+                        "name": "offset",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "hi"
+                        ],
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language: en, hi",
+                        "name": "accept-language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "default": "v1",
+                        "description": "App version",
+                        "name": "appversion",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "default": "v1",
+                        "description": "Route version",
+                        "name": "routeversion",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/internal_modules_user_v1.UserResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "User version"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "basicAuth": []
                     }
                 ],
-                "description": "Get all users with search",
+                "description": "Soft-delete a user; it can be restored until the retention period passes",
                 "consumes": [
                     "application/json"
                 ],
@@ -36,13 +1099,14 @@ const docTemplate = `{
                 "tags": [
                     "Users"
                 ],
-                "summary": "List users",
+                "summary": "Delete a user",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Search by name",
-                        "name": "search",
-                        "in": "query"
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "enum": [
@@ -66,7 +1130,7 @@ const docTemplate = `{
                     {
                         "type": "integer",
                         "default": 0,
-                        "description": "Time zone offset",
+                        "description": "Time zone offset in minutes east of UTC, used when timezone is not a valid IANA name",
                         "name": "offset",
                         "in": "header"
                     },
@@ -108,16 +1172,22 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
                     }
                 }
             },
-            "post": {
+            "patch": {
                 "security": [
                     {
                         "basicAuth": []
                     }
                 ],
-                "description": "Create a new user",
+                "description": "Update the given fields of a user. Send the ETag of the copy being edited as If-Match to get 409 instead of overwriting someone else's changes.",
                 "consumes": [
                     "application/json"
                 ],
@@ -127,17 +1197,31 @@ const docTemplate = `{
                 "tags": [
                     "Users"
                 ],
-                "summary": "Create a new user",
+                "summary": "Update a user",
                 "parameters": [
                     {
-                        "description": "Create user",
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/internal_modules_user_v1.CreateUserDTO"
+                            "$ref": "#/definitions/internal_modules_user_v1.UpdateUserDTO"
                         }
                     },
+                    {
+                        "type": "string",
+                        "example": "\"3\"",
+                        "description": "ETag from GET /users/{id}",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
                     {
                         "enum": [
                             "1",
@@ -160,7 +1244,7 @@ const docTemplate = `{
                     {
                         "type": "integer",
                         "default": 0,
-                        "description": "Time zone offset",
+                        "description": "Time zone offset in minutes east of UTC, used when timezone is not a valid IANA name",
                         "name": "offset",
                         "in": "header"
                     },
@@ -191,10 +1275,28 @@ const docTemplate = `{
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/internal_modules_user_v1.UserResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New user version"
+                            }
                         }
                     },
                     "400": {
@@ -208,6 +1310,118 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
                         }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/users/{id}/restore": {
+            "post": {
+                "security": [
+                    {
+                        "basicAuth": []
+                    }
+                ],
+                "description": "Restore a soft-deleted user that has not been purged yet",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Users"
+                ],
+                "summary": "Restore a deleted user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "1",
+                            "2",
+                            "3"
+                        ],
+                        "type": "string",
+                        "default": "1",
+                        "description": "Device OS: 1-Android, 2-iOS, 3-WEB",
+                        "name": "platform",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "default": "Asia/Kolkata",
+                        "description": "Time zone",
+                        "name": "timezone",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Time zone offset in minutes east of UTC, used when timezone is not a valid IANA name",
+                        "name": "offset",
+                        "in": "header"
+                    },
+                    {
+                        "enum": [
+                            "en",
+                            "hi"
+                        ],
+                        "type": "string",
+                        "default": "en",
+                        "description": "Language: en, hi",
+                        "name": "accept-language",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "default": "v1",
+                        "description": "App version",
+                        "name": "appversion",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "default": "v1",
+                        "description": "Route version",
+                        "name": "routeversion",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_addixit1_fiber-boilerplate_internal_config.APIResponse"
+                        }
                     }
                 }
             }
@@ -229,6 +1443,98 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_addixit1_fiber-boilerplate_internal_config.ListResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "hasNext": {
+                    "type": "boolean"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "nextPage": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "prevPage": {
+                    "type": "integer"
+                },
+                "statusCode": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "totalPages": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "internal_modules_apikey_v1.CreateAPIKeyDTO": {
+            "type": "object",
+            "required": [
+                "name",
+                "owner"
+            ],
+            "properties": {
+                "expires_at": {
+                    "type": "string",
+                    "example": "2027-01-01T00:00:00Z"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "billing-service"
+                },
+                "owner": {
+                    "type": "string",
+                    "example": "billing-team"
+                },
+                "rate_limit": {
+                    "type": "integer",
+                    "minimum": 0,
+                    "example": 120
+                },
+                "scopes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "users:read"
+                    ]
+                }
+            }
+        },
+        "internal_modules_appversion_v1.SetAppVersionDTO": {
+            "type": "object",
+            "required": [
+                "latest_version",
+                "min_version"
+            ],
+            "properties": {
+                "latest_version": {
+                    "type": "string",
+                    "example": "2.3.1"
+                },
+                "min_version": {
+                    "type": "string",
+                    "example": "2.0.0"
+                },
+                "update_url": {
+                    "type": "string",
+                    "example": "https://play.google.com/store/apps/details?id=com.example.app"
+                }
+            }
+        },
         "internal_modules_user_v1.CreateUserDTO": {
             "type": "object",
             "required": [
@@ -245,9 +1551,58 @@ const docTemplate = `{
                     "example": "Aman"
                 }
             }
+        },
+        "internal_modules_user_v1.UpdateUserDTO": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "type": "string",
+                    "minLength": 1,
+                    "example": "aman@gmail.com"
+                },
+                "name": {
+                    "type": "string",
+                    "minLength": 1,
+                    "example": "Aman Dixit"
+                }
+            }
+        },
+        "internal_modules_user_v1.UserResponseDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2024-01-01T12:00:00Z"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "aman@gmail.com"
+                },
+                "id": {
+                    "type": "string",
+                    "example": "507f1f77bcf86cd799439011"
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Aman"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2024-01-01T12:00:00Z"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
         }
     },
     "securityDefinitions": {
+        "APIKeyAuth": {
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
+        },
         "BasicAuth": {
             "type": "basic"
         },
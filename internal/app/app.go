@@ -1,45 +1,136 @@
 package app
 
 import (
+	"context"
+	"log"
+	"strconv"
+	"time"
+
 	"github.com/addixit1/fiber-boilerplate/internal/config"
 	errors "github.com/addixit1/fiber-boilerplate/internal/error"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/basicauth"
 	"github.com/addixit1/fiber-boilerplate/internal/lib/dbConnection"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/hotreload"
 	"github.com/addixit1/fiber-boilerplate/internal/lib/locale"
 	"github.com/addixit1/fiber-boilerplate/internal/lib/redis"
 	"github.com/addixit1/fiber-boilerplate/internal/lib/swagger"
+	"github.com/addixit1/fiber-boilerplate/internal/modules"
+	"github.com/addixit1/fiber-boilerplate/internal/modules/apikey"
+	"github.com/addixit1/fiber-boilerplate/internal/modules/appversion"
+	"github.com/addixit1/fiber-boilerplate/internal/modules/audit"
+	"github.com/addixit1/fiber-boilerplate/internal/modules/user"
+	"github.com/addixit1/fiber-boilerplate/internal/querybuilder"
 	"github.com/addixit1/fiber-boilerplate/internal/utils"
 	"github.com/gofiber/fiber/v2"
 )
 
 func New() *fiber.App {
 	// Load configuration
-	config.LoadEnv()
+	if err := config.Load(); err != nil {
+		utils.LogError(err.Error())
+		log.Fatal("Failed to load configuration")
+	}
+
+	// Periodically pick up rotated secrets (JWT key, Basic Auth password)
+	config.WatchSecrets(context.Background())
+
+	applyLogLevel(nil, &config.Config)
+	config.OnReload(applyLogLevel)
 
-	// Load locale files
+	// Load Basic Auth users (config + htpasswd file)
+	if err := basicauth.Load(); err != nil {
+		utils.LogError("Failed to load Basic Auth users: " + err.Error())
+		log.Fatal("Failed to load Basic Auth users")
+	}
+
+	// Load locale files (embedded, per module, then the override directory)
+	modules.RegisterLocales()
+	locale.SetOverrideDir(config.Config.Locale.Dir)
 	if err := locale.Load(); err != nil {
 		utils.LogError("Failed to load locale files: " + err.Error())
 	} else {
 		utils.LogSuccess("Locale files loaded successfully")
+		checkLocales()
 	}
 
 	// Print startup banner
-	utils.LogStartup("Fiber Boilerplate API", "1.0.0", "3010")
+	utils.LogStartup("Fiber Boilerplate API", "1.0.0", strconv.Itoa(config.Config.Port))
 
 	// Connect to databases
 	dbConnection.ConnectMongo()
 	redis.Init()
+	if err := apikey.EnsureIndexes(context.Background()); err != nil {
+		utils.LogError("Failed to create API key indexes: " + err.Error())
+	}
+	if err := user.EnsureIndexes(context.Background()); err != nil {
+		utils.LogError("Failed to create user indexes: " + err.Error())
+	}
+	if err := appversion.EnsureIndexes(context.Background()); err != nil {
+		utils.LogError("Failed to create app version indexes: " + err.Error())
+	}
+
+	if err := audit.EnsureIndexes(context.Background()); err != nil {
+		utils.LogError("Failed to create audit indexes: " + err.Error())
+	}
+	audit.Register()
+
+	// Purge soft-deleted documents once their retention has passed
+	querybuilder.SchedulePurge(context.Background(), config.Config.SoftDelete.PurgeInterval,
+		func() time.Duration { return config.Current().SoftDelete.Retention },
+		reportPurge, &user.User{})
 
 	// Initialize Fiber app
 	app := fiber.New(fiber.Config{
-		ErrorHandler: errors.Handler,
+		ErrorHandler:            errors.Handler,
+		EnableTrustedProxyCheck: len(config.Config.TrustedProxies) > 0,
+		TrustedProxies:          config.Config.TrustedProxies,
+		ProxyHeader:             config.Config.ProxyHeader,
 	})
 
 	registerMiddlewares(app)
 	registerRoutes(app)
-	swagger.Register(app)
+	if config.Config.SwaggerEnabled {
+		swagger.Register(app)
+		utils.LogServer("Swagger UI available at http://localhost" + config.Config.Addr() + "/swagger/index.html")
+	}
+
+	// Reload locales and runtime-safe config on file change or SIGHUP
+	hotreload.Watch(context.Background())
 
-	utils.LogServer("Swagger UI available at http://localhost:3010/swagger/index.html")
 	utils.LogSuccess("Application initialized successfully!")
 
 	return app
 }
+
+// applyLogLevel keeps the logger in sync with the (reloadable) log level
+func applyLogLevel(_, cfg *config.AppConfig) {
+	if err := utils.SetLogLevel(cfg.LogLevel); err != nil {
+		utils.LogError(err.Error())
+	}
+}
+
+// reportPurge logs the outcome of a scheduled soft delete purge
+func reportPurge(collection string, purged int64, err error) {
+	if err != nil {
+		utils.LogError("Failed to purge soft-deleted " + collection + ": " + err.Error())
+		return
+	}
+	if purged > 0 {
+		utils.LogInfo("Purged " + strconv.FormatInt(purged, 10) + " soft-deleted " + collection)
+	}
+}
+
+// checkLocales warns about locale bundles that differ from the default
+// language; with locale.strict set the server refuses to start
+func checkLocales() {
+	problems := 0
+	for _, report := range locale.Check() {
+		for _, line := range report.Problems() {
+			utils.LogWarning("Locale check: " + line)
+			problems++
+		}
+	}
+	if problems > 0 && config.Config.Locale.Strict {
+		log.Fatalf("Locale check found %d problems (run go run ./cmd/localecheck)", problems)
+	}
+}
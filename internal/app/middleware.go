@@ -16,6 +16,12 @@ func registerMiddlewares(app *fiber.App) {
 	// REQUEST HEADERS (Platform, Timezone, Language, etc.)
 	app.Use(middleware.RequestHeaders())
 
+	// APP VERSION (426 FORCE_UPDATE below the platform's minimum version)
+	app.Use(middleware.AppVersion())
+
+	// RATE LIMIT (rateLimit.*, off unless enabled; per IP, as no one is authenticated yet)
+	app.Use(middleware.RateLimit(""))
+
 	// DETAILED REQUEST LOGGER
 	app.Use(middleware.DetailedLogger())
 
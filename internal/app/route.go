@@ -2,11 +2,23 @@ package app
 
 import (
 	"github.com/gofiber/fiber/v2"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/versioning"
+	"github.com/addixit1/fiber-boilerplate/internal/modules/apikey/v1"
+	"github.com/addixit1/fiber-boilerplate/internal/modules/appversion/v1"
+	"github.com/addixit1/fiber-boilerplate/internal/modules/audit/v1"
+	"github.com/addixit1/fiber-boilerplate/internal/modules/lockout/v1"
 	"github.com/addixit1/fiber-boilerplate/internal/modules/user/v1"
 )
 
 func registerRoutes(app *fiber.App) {
-	api := app.Group("/api/v1")
+	versioning.Register("v1",
+		userv1.Routes,
+		apikeyv1.Routes,
+		lockoutv1.Routes,
+		appversionv1.Routes,
+		auditv1.Routes,
+	)
 
-	userv1.Routes(api)
+	// Serves /api/v1/... and /api/... with the routeversion header
+	versioning.Mount(app, "/api")
 }
@@ -9,6 +9,9 @@ const (
 	UNAUTHORIZED          = 401
 	ACCESS_FORBIDDEN      = 403
 	NOT_FOUND             = 404
+	CONFLICT              = 409
+	UPGRADE_REQUIRED      = 426
+	TOO_MANY_REQUESTS     = 429
 	INTERNAL_SERVER_ERROR = 500
 )
 
@@ -32,6 +35,16 @@ const (
 	TYPE_BLOCK_USER           = "BLOCK_USER"
 	TYPE_UNBLOCK_USER         = "UNBLOCK_USER"
 	TYPE_NOTIFICATION_DELETED = "NOTIFICATION_DELETED"
+	TYPE_API_KEY_CREATED      = "API_KEY_CREATED"
+	TYPE_API_KEY_ROTATED      = "API_KEY_ROTATED"
+	TYPE_API_KEY_REVOKED      = "API_KEY_REVOKED"
+	TYPE_LOCKOUT_CLEARED      = "LOCKOUT_CLEARED"
+	TYPE_APP_UP_TO_DATE       = "APP_UP_TO_DATE"
+	TYPE_UPDATE_AVAILABLE     = "UPDATE_AVAILABLE"
+	TYPE_APP_VERSION_UPDATED  = "APP_VERSION_UPDATED"
+	TYPE_USER_DELETED         = "USER_DELETED"
+	TYPE_USER_RESTORED        = "USER_RESTORED"
+	TYPE_USER_UPDATED         = "USER_UPDATED"
 
 	// Error Types
 	TYPE_ERROR                      = "ERROR"
@@ -58,16 +71,26 @@ const (
 	TYPE_PASSWORD_REUSE             = "PASSWORD_REUSE"
 	TYPE_MOBILE_NO_NOT_VERIFIED     = "MOBILE_NO_NOT_VERIFIED"
 	TYPE_MOBILE_NO_ALREADY_EXIST    = "MOBILE_NO_ALREADY_EXIST"
+	TYPE_ACCESS_FORBIDDEN           = "ACCESS_FORBIDDEN"
+	TYPE_ACCOUNT_LOCKED             = "ACCOUNT_LOCKED"
+	TYPE_INVALID_API_KEY            = "INVALID_API_KEY"
+	TYPE_API_KEY_EXPIRED            = "API_KEY_EXPIRED"
+	TYPE_API_KEY_NOT_FOUND          = "API_KEY_NOT_FOUND"
+	TYPE_TOO_MANY_REQUESTS          = "TOO_MANY_REQUESTS"
+	TYPE_CAPTCHA_REQUIRED           = "CAPTCHA_REQUIRED"
+	TYPE_VALIDATION_ERROR           = "VALIDATION_ERROR"
+	TYPE_FORCE_UPDATE               = "FORCE_UPDATE"
+	TYPE_UNSUPPORTED_API_VERSION    = "UNSUPPORTED_API_VERSION"
+	TYPE_INVALID_QUERY              = "INVALID_QUERY"
+	TYPE_VERSION_CONFLICT           = "VERSION_CONFLICT"
+	TYPE_API_KEY_ALREADY_REVOKED    = "API_KEY_ALREADY_REVOKED"
 )
 
 const (
-	BASIC_USERNAME = "fiber"
-	BASIC_PASSWORD = "fiber@123"
-)
-
-
-const (
-	USERS_COLLECTION= "users"
-	ADMIN_COLLECTION = "admins"
+	USERS_COLLECTION          = "users"
+	ADMIN_COLLECTION          = "admins"
 	LOGIN_SESSIONS_COLLECTION = "login_sessions"
-)
\ No newline at end of file
+	API_KEYS_COLLECTION       = "api_keys"
+	APP_VERSIONS_COLLECTION   = "app_versions"
+	AUDIT_LOGS_COLLECTION     = "audit_logs"
+)
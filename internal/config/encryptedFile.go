@@ -0,0 +1,172 @@
+package config
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// encryptedFileVersion is bumped whenever the on-disk format changes
+const encryptedFileVersion = 1
+
+// encryptedFile is the on-disk layout: an AES-256-GCM sealed JSON object of name → secret
+type encryptedFile struct {
+	Version int    `json:"version"`
+	Nonce   string `json:"nonce"`
+	Data    string `json:"data"`
+}
+
+// EncryptedFileProvider reads secrets from a local AES-256-GCM encrypted file.
+// The file is decrypted again whenever its modification time changes, so
+// replacing it on disk rotates the secrets it contains.
+type EncryptedFileProvider struct {
+	path string
+	key  []byte
+
+	mu      sync.Mutex
+	modTime time.Time
+	secrets map[string]string
+}
+
+// NewEncryptedFileProvider creates a provider for the file at path, sealed with a 32-byte key
+func NewEncryptedFileProvider(path string, key []byte) *EncryptedFileProvider {
+	return &EncryptedFileProvider{path: path, key: key}
+}
+
+// Name identifies the provider in error messages
+func (p *EncryptedFileProvider) Name() string {
+	return "encrypted file " + p.path
+}
+
+// GetSecret returns the secret stored under key
+func (p *EncryptedFileProvider) GetSecret(_ context.Context, key string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	info, err := os.Stat(p.path)
+	if err != nil {
+		return "", err
+	}
+
+	if p.secrets == nil || !info.ModTime().Equal(p.modTime) {
+		data, err := os.ReadFile(p.path)
+		if err != nil {
+			return "", err
+		}
+		secrets, err := DecryptSecrets(p.key, data)
+		if err != nil {
+			return "", err
+		}
+		p.secrets = secrets
+		p.modTime = info.ModTime()
+	}
+
+	value, ok := p.secrets[key]
+	if !ok {
+		return "", ErrSecretNotFound
+	}
+	return value, nil
+}
+
+// GenerateSecretsKey returns a new random key, base64 encoded for SECRETS_KEY
+func GenerateSecretsKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// DecodeSecretsKey decodes a base64 SECRETS_KEY into a 32-byte AES key
+func DecodeSecretsKey(encoded string) ([]byte, error) {
+	if encoded == "" {
+		return nil, errors.New("SECRETS_KEY is required for the file secret provider")
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("SECRETS_KEY is not valid base64: %w", err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("SECRETS_KEY must decode to 32 bytes, got %d", len(key))
+	}
+	return key, nil
+}
+
+// EncryptSecrets seals secrets into the encrypted file format
+func EncryptSecrets(key []byte, secrets map[string]string) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	plain, err := json.Marshal(secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(encryptedFile{
+		Version: encryptedFileVersion,
+		Nonce:   base64.StdEncoding.EncodeToString(nonce),
+		Data:    base64.StdEncoding.EncodeToString(gcm.Seal(nil, nonce, plain, nil)),
+	}, "", "  ")
+}
+
+// DecryptSecrets opens data produced by EncryptSecrets
+func DecryptSecrets(key []byte, data []byte) (map[string]string, error) {
+	var file encryptedFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("invalid secrets file: %w", err)
+	}
+	if file.Version != encryptedFileVersion {
+		return nil, fmt.Errorf("unsupported secrets file version %d", file.Version)
+	}
+
+	nonce, err := base64.StdEncoding.DecodeString(file.Nonce)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secrets file nonce: %w", err)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(file.Data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secrets file data: %w", err)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != gcm.NonceSize() {
+		return nil, errors.New("invalid secrets file nonce size")
+	}
+
+	plain, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, errors.New("failed to decrypt secrets file: wrong key or corrupted data")
+	}
+
+	secrets := map[string]string{}
+	if err := json.Unmarshal(plain, &secrets); err != nil {
+		return nil, fmt.Errorf("invalid secrets payload: %w", err)
+	}
+	return secrets, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -1,48 +1,287 @@
 package config
 
 import (
-	"log"
-	"os"
+	"fmt"
+	"time"
 
 	"github.com/joho/godotenv"
 )
 
+// AppConfig holds the typed application configuration.
+//
+// Every field is resolved from, in increasing order of precedence:
+// the `default` tag, the config file (YAML or TOML, keyed by the `config`
+// tag), the .env file and finally real environment variables (`env` tag).
+// Fields tagged `secret` that are not set in the environment are looked up
+// in the configured SecretProvider. Fields tagged `reload` are runtime-safe
+// and are swapped in by Reload without a restart.
 type AppConfig struct {
-	Env       string
-	Port      string
-	MongoURI  string
-	JWTSecret string
-	RedisURI  string
-	MongoDbName string
-	DebugStatus string
+	Env            string   `config:"env" env:"ENV" default:"development" validate:"oneof=development staging production"`
+	Port           int      `config:"port" env:"PORT" default:"3010" validate:"min=1,max=65535"`
+	TrustedProxies []string `config:"trustedProxies" env:"TRUSTED_PROXIES"`
+	ProxyHeader    string   `config:"proxyHeader" env:"PROXY_HEADER"`
+	SwaggerEnabled bool     `config:"swaggerEnabled" env:"SWAGGER_ENABLED" default:"true"`
+
+	LogLevel     string          `config:"logLevel" env:"LOG_LEVEL" default:"debug" validate:"oneof=debug info warning error" reload:"true"`
+	FeatureFlags map[string]bool `config:"featureFlags" env:"FEATURE_FLAGS" reload:"true"`
+
+	Mongo       MongoConfig      `config:"mongo"`
+	Redis       RedisConfig      `config:"redis"`
+	JWT         JWTConfig        `config:"jwt"`
+	BasicAuth   BasicAuthConfig  `config:"basicAuth"`
+	BruteForce  BruteForceConfig `config:"bruteForce"`
+	Secrets     SecretsConfig    `config:"secrets"`
+	APIKeys     APIKeyConfig     `config:"apiKeys"`
+	RateLimit   RateLimitConfig  `config:"rateLimit"`
+	HotReload   HotReloadConfig  `config:"hotReload"`
+	Locale      LocaleConfig     `config:"locale"`
+	AppVersion  AppVersionConfig `config:"appVersion"`
+	APIVersions APIVersionConfig `config:"apiVersions"`
+	SoftDelete  SoftDeleteConfig `config:"softDelete"`
+	Audit       AuditConfig      `config:"audit"`
+}
+
+// MongoConfig holds MongoDB connection settings
+type MongoConfig struct {
+	URI              string        `config:"uri" env:"MONGO_URI" validate:"required" secret:"true"`
+	DbName           string        `config:"dbName" env:"MONGO_DB_NAME" validate:"required"`
+	Debug            bool          `config:"debug" env:"MONGO_DEBUG" default:"false"`
+	ConnectTimeout   time.Duration `config:"connectTimeout" env:"MONGO_CONNECT_TIMEOUT" default:"10s" validate:"min=1s"`
+	OperationTimeout time.Duration `config:"operationTimeout" env:"MONGO_OPERATION_TIMEOUT" default:"10s" validate:"min=1ms"`
+	SearchIndex      string        `config:"searchIndex" env:"MONGO_SEARCH_INDEX"`
+}
+
+// RedisConfig holds Redis connection settings
+type RedisConfig struct {
+	Addr         string        `config:"addr" env:"REDIS_URL" default:"localhost:6379" validate:"required"`
+	Password     string        `config:"password" env:"REDIS_PASSWORD" secret:"true"`
+	DB           int           `config:"db" env:"REDIS_DB" default:"0" validate:"min=0,max=15"`
+	DialTimeout  time.Duration `config:"dialTimeout" env:"REDIS_DIAL_TIMEOUT" default:"5s" validate:"min=1ms"`
+	ReadTimeout  time.Duration `config:"readTimeout" env:"REDIS_READ_TIMEOUT" default:"3s" validate:"min=1ms"`
+	WriteTimeout time.Duration `config:"writeTimeout" env:"REDIS_WRITE_TIMEOUT" default:"3s" validate:"min=1ms"`
+	PoolSize     int           `config:"poolSize" env:"REDIS_POOL_SIZE" default:"20" validate:"min=1"`
+	MinIdleConns int           `config:"minIdleConns" env:"REDIS_MIN_IDLE_CONNS" default:"5" validate:"min=0"`
+}
+
+// JWTConfig holds JWT signing settings.
+// Read the key through JWTKey, which follows rotations.
+type JWTConfig struct {
+	Secret string `config:"secret" env:"JWT_SECRET" validate:"required,min=16" secret:"true"`
+}
+
+// BasicAuthConfig holds the users accepted by the BasicAuth middleware.
+// Users come from the single Username/Password pair, the Users list in the
+// config file and the htpasswd-style UsersFile; at least one is required.
+// Read Password through BasicAuthPassword, which follows rotations.
+type BasicAuthConfig struct {
+	Username  string          `config:"username" env:"BASIC_AUTH_USERNAME"`
+	Password  string          `config:"password" env:"BASIC_AUTH_PASSWORD" validate:"min=8" secret:"true"`
+	Scopes    []string        `config:"scopes" env:"BASIC_AUTH_SCOPES" default:"*"`
+	Users     []BasicAuthUser `config:"users"`
+	UsersFile string          `config:"usersFile" env:"BASIC_AUTH_USERS_FILE"`
+	Realm     string          `config:"realm" env:"BASIC_AUTH_REALM" default:"Restricted"`
+}
+
+// BasicAuthUser is a Basic Auth user declared in the config file
+type BasicAuthUser struct {
+	Username     string   `config:"username"`
+	PasswordHash string   `config:"passwordHash"`
+	Scopes       []string `config:"scopes"`
+}
+
+// SecretsConfig selects where secrets come from when they are not set in the environment
+type SecretsConfig struct {
+	Provider       string        `config:"provider" env:"SECRETS_PROVIDER" default:"none" validate:"oneof=none file"`
+	File           string        `config:"file" env:"SECRETS_FILE" default:"secrets.enc"`
+	Key            string        `config:"key" env:"SECRETS_KEY" secret:"true"`
+	ReloadInterval time.Duration `config:"reloadInterval" env:"SECRETS_RELOAD_INTERVAL" default:"0s" validate:"min=0s"`
+	RotationGrace  time.Duration `config:"rotationGrace" env:"SECRETS_ROTATION_GRACE" default:"15m" validate:"min=0s" reload:"true"`
+}
+
+// APIKeyConfig holds settings for service-to-service API keys
+type APIKeyConfig struct {
+	Header           string        `config:"header" env:"API_KEY_HEADER" default:"X-API-Key" validate:"required"`
+	DefaultRateLimit int           `config:"defaultRateLimit" env:"API_KEY_DEFAULT_RATE_LIMIT" default:"60" validate:"min=1"`
+	UsageRetention   time.Duration `config:"usageRetention" env:"API_KEY_USAGE_RETENTION" default:"2160h" validate:"min=24h"`
 }
 
+// RateLimitConfig holds the default request rate limit and per route group overrides
+type RateLimitConfig struct {
+	Enabled   bool                      `config:"enabled" env:"RATE_LIMIT_ENABLED" default:"false" reload:"true"`
+	Max       int                       `config:"max" env:"RATE_LIMIT_MAX" default:"100" validate:"min=1" reload:"true"`
+	Window    time.Duration             `config:"window" env:"RATE_LIMIT_WINDOW" default:"1m" validate:"min=1s" reload:"true"`
+	Algorithm string                    `config:"algorithm" env:"RATE_LIMIT_ALGORITHM" default:"sliding_window" validate:"oneof=sliding_window token_bucket" reload:"true"`
+	Key       string                    `config:"key" env:"RATE_LIMIT_KEY" default:"ip" validate:"oneof=ip user api_key" reload:"true"`
+	Groups    map[string]RateLimitGroup `config:"groups" reload:"true"`
+}
+
+// RateLimitGroup overrides the default rate limit for a route group.
+// Zero values inherit from RateLimitConfig.
+type RateLimitGroup struct {
+	Max       int           `config:"max" validate:"min=0"`
+	Window    time.Duration `config:"window" validate:"min=0s"`
+	Algorithm string        `config:"algorithm"`
+	Key       string        `config:"key"`
+}
+
+// Group returns the effective rate limit of a route group
+func (r RateLimitConfig) Group(name string) RateLimitGroup {
+	group := r.Groups[name]
+	if group.Max == 0 {
+		group.Max = r.Max
+	}
+	if group.Window == 0 {
+		group.Window = r.Window
+	}
+	if group.Algorithm == "" {
+		group.Algorithm = r.Algorithm
+	}
+	if group.Key == "" {
+		group.Key = r.Key
+	}
+	return group
+}
+
+// BruteForceConfig controls failed credential tracking per account and per IP
+type BruteForceConfig struct {
+	MaxAccountAttempts int           `config:"maxAccountAttempts" env:"BRUTE_FORCE_MAX_ACCOUNT_ATTEMPTS" default:"5" validate:"min=1" reload:"true"`
+	MaxIPAttempts      int           `config:"maxIPAttempts" env:"BRUTE_FORCE_MAX_IP_ATTEMPTS" default:"20" validate:"min=1" reload:"true"`
+	AttemptWindow      time.Duration `config:"attemptWindow" env:"BRUTE_FORCE_ATTEMPT_WINDOW" default:"15m" validate:"min=1s" reload:"true"`
+	LockoutDuration    time.Duration `config:"lockoutDuration" env:"BRUTE_FORCE_LOCKOUT_DURATION" default:"15m" validate:"min=1s" reload:"true"`
+	CaptchaAfter       int           `config:"captchaAfter" env:"BRUTE_FORCE_CAPTCHA_AFTER" default:"3" validate:"min=0" reload:"true"`
+	DelayBase          time.Duration `config:"delayBase" env:"BRUTE_FORCE_DELAY_BASE" default:"200ms" validate:"min=0s" reload:"true"`
+	MaxDelay           time.Duration `config:"maxDelay" env:"BRUTE_FORCE_MAX_DELAY" default:"3s" validate:"min=0s" reload:"true"`
+}
+
+// LocaleConfig controls where locale bundles are read from and how they are checked
+type LocaleConfig struct {
+	Dir    string `config:"dir" env:"LOCALE_DIR"`
+	Strict bool   `config:"strict" env:"LOCALE_STRICT" default:"false"`
+}
+
+// AppVersionConfig controls minimum app version enforcement for mobile clients
+type AppVersionConfig struct {
+	Enabled  bool          `config:"enabled" env:"APP_VERSION_CHECK_ENABLED" default:"true" reload:"true"`
+	CacheTTL time.Duration `config:"cacheTTL" env:"APP_VERSION_CACHE_TTL" default:"5m" validate:"min=0s" reload:"true"`
+}
+
+// APIVersionConfig controls route version dispatch and deprecation notices.
+// Sunset dates are YYYY-MM-DD, e.g. API_VERSION_SUNSET=v1=2027-01-31.
+type APIVersionConfig struct {
+	Default    string            `config:"default" env:"API_DEFAULT_VERSION" default:"v1" validate:"required" reload:"true"`
+	Deprecated []string          `config:"deprecated" env:"API_DEPRECATED_VERSIONS" reload:"true"`
+	Sunset     map[string]string `config:"sunset" env:"API_VERSION_SUNSET" reload:"true"`
+	Link       string            `config:"link" env:"API_DEPRECATION_LINK" reload:"true"`
+}
+
+// IsDeprecated reports whether version is listed as deprecated
+func (a APIVersionConfig) IsDeprecated(version string) bool {
+	for _, deprecated := range a.Deprecated {
+		if deprecated == version {
+			return true
+		}
+	}
+	return false
+}
+
+// SunsetOf returns the sunset date of version, if one is set
+func (a APIVersionConfig) SunsetOf(version string) (time.Time, bool) {
+	sunset, err := time.Parse(time.DateOnly, a.Sunset[version])
+	return sunset, err == nil
+}
+
+// SoftDeleteConfig controls when soft-deleted documents are purged for good
+type SoftDeleteConfig struct {
+	Retention     time.Duration `config:"retention" env:"SOFT_DELETE_RETENTION" default:"720h" validate:"min=1h" reload:"true"`
+	PurgeInterval time.Duration `config:"purgeInterval" env:"SOFT_DELETE_PURGE_INTERVAL" default:"1h" validate:"min=0s"`
+}
+
+// AuditConfig controls the audit trail of writes made through BaseRepository.
+// Collections is read once at startup; an empty list audits every collection.
+type AuditConfig struct {
+	Enabled     bool          `config:"enabled" env:"AUDIT_ENABLED" default:"true" reload:"true"`
+	Retention   time.Duration `config:"retention" env:"AUDIT_RETENTION" default:"2160h" validate:"min=1h"`
+	Collections []string      `config:"collections" env:"AUDIT_COLLECTIONS" default:"users,api_keys,app_versions"`
+	Redact      []string      `config:"redact" env:"AUDIT_REDACT_FIELDS" default:"hash,password,secret,token" reload:"true"`
+}
+
+// HotReloadConfig controls the config and locale file watcher
+type HotReloadConfig struct {
+	Enabled  bool          `config:"enabled" env:"HOT_RELOAD_ENABLED" default:"true"`
+	Interval time.Duration `config:"interval" env:"HOT_RELOAD_INTERVAL" default:"2s" validate:"min=100ms"`
+}
+
+// Config is the configuration loaded at startup. Values tagged `reload`
+// may change afterwards; read those through Current().
 var Config AppConfig
 
-func LoadEnv() {
+// configFile is the config file used by the last successful load, if any
+var configFile string
+
+// Load resolves the configuration from all sources and validates it.
+// The returned error aggregates every problem found, not just the first.
+func Load() error {
+	// .env never overrides variables already present in the environment
 	_ = godotenv.Load()
 
-	Config = AppConfig{
-		Env:       getEnv("ENV", "development"),
-		Port:      getEnv("PORT", "3010"),
-		MongoURI:  getEnv("MONGO_URI", ""),
-		JWTSecret: getEnv("JWT_SECRET", ""),
-		RedisURI:  getEnv("REDIS_URL", "localhost:6379"),
-		MongoDbName: getEnv("MONGO_DB_NAME", ""),
-		DebugStatus: getEnv("MONGO_DEBUG", "false"),
+	cfg, file, provider, err := resolve()
+	if err != nil {
+		return err
 	}
 
-	if Config.MongoURI == "" {
-		log.Fatal("MONGO_URI is required")
+	Config = cfg
+	configFile = file
+	secretProvider = provider
+	current.Store(&cfg)
+	seedSecrets(&cfg)
+	return nil
+}
+
+// resolve runs every configuration layer and validates the result
+func resolve() (AppConfig, string, SecretProvider, error) {
+	cfg := AppConfig{}
+	errs := applyDefaults(&cfg)
+
+	file, err := configFilePath()
+	if err != nil {
+		return cfg, "", nil, err
 	}
-	if Config.JWTSecret == "" {
-		log.Fatal("JWT_SECRET is required")
+	if file != "" {
+		errs = append(errs, applyFile(&cfg, file)...)
 	}
-}
 
-func getEnv(key, def string) string {
-	if v := os.Getenv(key); v != "" {
-		return v
+	errs = append(errs, applyEnv(&cfg)...)
+
+	provider, err := newSecretProvider(cfg.Secrets)
+	if err != nil {
+		errs = append(errs, FieldError{Path: "secrets.key", Env: "SECRETS_KEY", Message: err.Error()})
+	}
+	errs = append(errs, applySecrets(&cfg, provider)...)
+
+	errs = append(errs, validate(&cfg, errs)...)
+	if len(errs) > 0 {
+		return cfg, file, provider, errs
 	}
-	return def
+	return cfg, file, provider, nil
+}
+
+// File returns the config file in use, or "" when configuration comes only from the environment
+func File() string {
+	return configFile
+}
+
+// IsProduction reports whether the app runs in production mode
+func (c AppConfig) IsProduction() bool {
+	return c.Env == "production"
+}
+
+// Feature reports whether the named feature flag is enabled. Flags are
+// hot reloadable, so check them on config.Current().
+func (c AppConfig) Feature(name string) bool {
+	return c.FeatureFlags[name]
+}
+
+// Addr returns the listen address for the HTTP server
+func (c AppConfig) Addr() string {
+	return fmt.Sprintf(":%d", c.Port)
 }
@@ -0,0 +1,380 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultConfigFiles are looked up in the working directory when CONFIG_FILE is not set
+var defaultConfigFiles = []string{"config.yaml", "config.yml", "config.toml"}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// field is a single leaf value of AppConfig together with its tags
+type field struct {
+	value    reflect.Value
+	path     string
+	env      string
+	def      string
+	hasDef   bool
+	validate string
+	secret   bool
+	reload   bool
+}
+
+// fields walks cfg recursively and returns every leaf field
+func fields(cfg interface{}) []field {
+	var out []field
+	walk(reflect.ValueOf(cfg).Elem(), "", &out)
+	return out
+}
+
+func walk(v reflect.Value, prefix string, out *[]field) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		name := sf.Tag.Get("config")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+
+		fv := v.Field(i)
+		if sf.Type.Kind() == reflect.Struct && sf.Type != reflect.TypeOf(time.Time{}) {
+			walk(fv, path, out)
+			continue
+		}
+
+		def, hasDef := sf.Tag.Lookup("default")
+		*out = append(*out, field{
+			value:    fv,
+			path:     path,
+			env:      sf.Tag.Get("env"),
+			def:      def,
+			hasDef:   hasDef,
+			validate: sf.Tag.Get("validate"),
+			secret:   sf.Tag.Get("secret") == "true",
+			reload:   sf.Tag.Get("reload") == "true",
+		})
+	}
+}
+
+// applyDefaults sets every field that has a `default` tag
+func applyDefaults(cfg *AppConfig) Errors {
+	var errs Errors
+	for _, f := range fields(cfg) {
+		if !f.hasDef {
+			continue
+		}
+		if err := setString(f.value, f.def); err != nil {
+			errs = append(errs, FieldError{Path: f.path, Env: f.env, Message: "invalid default: " + err.Error()})
+		}
+	}
+	return errs
+}
+
+// configFilePath returns the config file to load, or "" when none is present
+func configFilePath() (string, error) {
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("config file %s: %w", path, err)
+		}
+		return path, nil
+	}
+
+	for _, name := range defaultConfigFiles {
+		if _, err := os.Stat(name); err == nil {
+			return name, nil
+		}
+	}
+	return "", nil
+}
+
+// readConfigFile parses a YAML or TOML file into a generic map
+func readConfigFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	values := map[string]interface{}{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &values)
+	case ".toml":
+		err = toml.Unmarshal(data, &values)
+	default:
+		return nil, fmt.Errorf("unsupported config file format: %s", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+
+	return values, nil
+}
+
+// applyFile overlays values from the config file onto cfg
+func applyFile(cfg *AppConfig, path string) Errors {
+	values, err := readConfigFile(path)
+	if err != nil {
+		return Errors{{Path: path, Message: err.Error()}}
+	}
+
+	var errs Errors
+	known := map[string]bool{}
+	for _, f := range fields(cfg) {
+		known[f.path] = true
+
+		raw, ok := lookupPath(values, f.path)
+		if !ok || raw == nil {
+			continue
+		}
+		if err := setAny(f.value, raw); err != nil {
+			errs = append(errs, FieldError{Path: f.path, Env: f.env, Message: fmt.Sprintf("invalid value in %s: %s", path, err)})
+		}
+	}
+
+	unknown := leafPaths(values, "")
+	sort.Strings(unknown)
+	for _, key := range unknown {
+		if !known[key] && !isKnownPrefix(known, key) {
+			errs = append(errs, FieldError{Path: key, Message: "unknown key in " + path})
+		}
+	}
+
+	return errs
+}
+
+// applyEnv overlays environment variables onto cfg. Every variable can
+// instead be read from a file named by its *_FILE variant (Docker/Kubernetes secrets).
+func applyEnv(cfg *AppConfig) Errors {
+	var errs Errors
+	for _, f := range fields(cfg) {
+		if f.env == "" {
+			continue
+		}
+		raw, ok, err := lookupEnv(f.env)
+		if err != nil {
+			errs = append(errs, FieldError{Path: f.path, Env: f.env, Message: err.Error()})
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if err := setString(f.value, raw); err != nil {
+			errs = append(errs, FieldError{Path: f.path, Env: f.env, Message: err.Error()})
+		}
+	}
+	return errs
+}
+
+// lookupPath resolves a dotted path such as "mongo.uri" in a nested map
+func lookupPath(values map[string]interface{}, path string) (interface{}, bool) {
+	parts := strings.Split(path, ".")
+	var current interface{} = values
+	for _, part := range parts {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = m[part]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// leafPaths lists the dotted paths of all non-map values in a nested map
+func leafPaths(values map[string]interface{}, prefix string) []string {
+	var paths []string
+	for key, v := range values {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		if nested, ok := v.(map[string]interface{}); ok {
+			paths = append(paths, leafPaths(nested, path)...)
+			continue
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+// isKnownPrefix reports whether key addresses an entry inside a map-typed field
+func isKnownPrefix(known map[string]bool, key string) bool {
+	for i := strings.LastIndex(key, "."); i > 0; i = strings.LastIndex(key[:i], ".") {
+		if known[key[:i]] {
+			return true
+		}
+	}
+	return false
+}
+
+// setAny assigns a decoded file value to v
+func setAny(v reflect.Value, raw interface{}) error {
+	switch typed := raw.(type) {
+	case []interface{}:
+		if v.Kind() != reflect.Slice {
+			return fmt.Errorf("expected %s, got a list", v.Type())
+		}
+		slice := reflect.MakeSlice(v.Type(), 0, len(typed))
+		for i, item := range typed {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := setAny(elem, item); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+			slice = reflect.Append(slice, elem)
+		}
+		v.Set(slice)
+		return nil
+	case map[string]interface{}:
+		if v.Kind() == reflect.Struct {
+			return setStruct(v, typed)
+		}
+		if v.Kind() != reflect.Map {
+			return fmt.Errorf("expected %s, got a map", v.Type())
+		}
+		m := reflect.MakeMapWithSize(v.Type(), len(typed))
+		for key, item := range typed {
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := setAny(elem, item); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+			m.SetMapIndex(reflect.ValueOf(key), elem)
+		}
+		v.Set(m)
+		return nil
+	default:
+		return setString(v, fmt.Sprint(raw))
+	}
+}
+
+// setStruct fills a struct list or map entry (e.g. basicAuth.users[0]) from a file map
+func setStruct(v reflect.Value, values map[string]interface{}) error {
+	var out []field
+	walk(v, "", &out)
+
+	known := map[string]bool{}
+	for _, f := range out {
+		known[f.path] = true
+		if f.hasDef {
+			if err := setString(f.value, f.def); err != nil {
+				return fmt.Errorf("%s: %w", f.path, err)
+			}
+		}
+		raw, ok := lookupPath(values, f.path)
+		if !ok || raw == nil {
+			continue
+		}
+		if err := setAny(f.value, raw); err != nil {
+			return fmt.Errorf("%s: %w", f.path, err)
+		}
+	}
+
+	for _, key := range leafPaths(values, "") {
+		if !known[key] {
+			return fmt.Errorf("unknown key %s", key)
+		}
+	}
+	return nil
+}
+
+// setString parses raw into the type of v.
+// Lists are comma separated and maps use "key=value" pairs.
+func setString(v reflect.Value, raw string) error {
+	raw = strings.TrimSpace(raw)
+
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q", raw)
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(raw, 10, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid unsigned integer %q", raw)
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(raw, v.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		v.SetFloat(n)
+	case reflect.Slice:
+		slice := reflect.MakeSlice(v.Type(), 0, 0)
+		for _, part := range strings.Split(raw, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := setString(elem, part); err != nil {
+				return err
+			}
+			slice = reflect.Append(slice, elem)
+		}
+		v.Set(slice)
+	case reflect.Map:
+		m := reflect.MakeMap(v.Type())
+		for _, pair := range strings.Split(raw, ",") {
+			pair = strings.TrimSpace(pair)
+			if pair == "" {
+				continue
+			}
+			key, value, ok := strings.Cut(pair, "=")
+			if !ok {
+				return fmt.Errorf("invalid map entry %q, expected key=value", pair)
+			}
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := setString(elem, value); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+			m.SetMapIndex(reflect.ValueOf(strings.TrimSpace(key)), elem)
+		}
+		v.Set(m)
+	default:
+		return fmt.Errorf("unsupported config type %s", v.Type())
+	}
+	return nil
+}
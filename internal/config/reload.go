@@ -0,0 +1,105 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// current is the latest configuration, including hot-reloaded values
+var current atomic.Pointer[AppConfig]
+
+var (
+	reloadMu    sync.Mutex
+	reloadHooks []func(old, new *AppConfig)
+)
+
+// Change describes a configuration value that differs after a reload
+type Change struct {
+	Path    string
+	Old     string
+	New     string
+	Applied bool
+}
+
+func (c Change) String() string {
+	if !c.Applied {
+		return c.Path + " changed (restart required)"
+	}
+	return fmt.Sprintf("%s: %s → %s", c.Path, c.Old, c.New)
+}
+
+// Current returns the latest configuration. Unlike Config, values tagged
+// `reload` reflect the most recent successful Reload.
+func Current() *AppConfig {
+	if cfg := current.Load(); cfg != nil {
+		return cfg
+	}
+	return &Config
+}
+
+// OnReload registers fn to run after every Reload that applied changes
+func OnReload(fn func(old, new *AppConfig)) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+	reloadHooks = append(reloadHooks, fn)
+}
+
+// Reload re-resolves the configuration and atomically swaps in the values
+// tagged `reload`. Nothing is applied when validation fails. Changes to
+// other fields are reported but only take effect after a restart.
+func Reload() ([]Change, error) {
+	reloadMu.Lock()
+	defer reloadMu.Unlock()
+
+	loaded, _, _, err := resolve()
+	if err != nil {
+		return nil, err
+	}
+
+	old := Current()
+	next := *old
+	changes := diff(old, &loaded, &next)
+
+	applied := false
+	for _, change := range changes {
+		applied = applied || change.Applied
+	}
+	if !applied {
+		return changes, nil
+	}
+
+	current.Store(&next)
+	for _, hook := range reloadHooks {
+		hook(old, &next)
+	}
+	return changes, nil
+}
+
+// diff compares old and loaded field by field and copies runtime-safe
+// changes into next. Values of other fields are not reported since they
+// may hold secrets.
+func diff(old, loaded, next *AppConfig) []Change {
+	oldFields := fields(old)
+	loadedFields := fields(loaded)
+	nextFields := fields(next)
+
+	var changes []Change
+	for i, f := range loadedFields {
+		before := oldFields[i].value.Interface()
+		after := f.value.Interface()
+		if reflect.DeepEqual(before, after) {
+			continue
+		}
+
+		change := Change{Path: f.path, Applied: f.reload}
+		if f.reload {
+			change.Old = fmt.Sprint(before)
+			change.New = fmt.Sprint(after)
+			nextFields[i].value.Set(f.value)
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"github.com/addixit1/fiber-boilerplate/internal/lib/locale"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/validator"
 )
 
 // APIResponse represents a standard API response
@@ -17,18 +18,68 @@ type ListResponse struct {
 	StatusCode int         `json:"statusCode"`
 	Type       string      `json:"type"`
 	Message    string      `json:"message"`
-	Data       interface{} `json:"data,omitempty"`
-	Total      int64       `json:"total,omitempty"`
-	Page       int         `json:"page,omitempty"`
-	Limit      int         `json:"limit,omitempty"`
+	Data       interface{} `json:"data"`
+	Pagination
+}
+
+// Pagination is the page metadata of a ListResponse. Fields are always
+// present; total and totalPages are null when counting was skipped, and
+// nextPage and prevPage are null at either end.
+type Pagination struct {
+	Total      *int64 `json:"total"`
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	TotalPages *int   `json:"totalPages"`
+	HasNext    bool   `json:"hasNext"`
+	NextPage   *int   `json:"nextPage"`
+	PrevPage   *int   `json:"prevPage"`
+}
+
+// NewPagination builds the page metadata of a ListResponse, e.g. from a
+// querybuilder.PageInfo. A negative total means it was not counted.
+func NewPagination(page, limit int, total int64, hasNext bool) Pagination {
+	pagination := Pagination{Page: page, Limit: limit, HasNext: hasNext}
+	if total >= 0 && limit > 0 {
+		totalPages := int((total + int64(limit) - 1) / int64(limit))
+		pagination.Total, pagination.TotalPages = &total, &totalPages
+	}
+	if hasNext {
+		next := page + 1
+		pagination.NextPage = &next
+	}
+	if page > 1 {
+		prev := page - 1
+		pagination.PrevPage = &prev
+	}
+	return pagination
+}
+
+// CursorListResponse represents a list response with cursor pagination
+type CursorListResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Type       string      `json:"type"`
+	Message    string      `json:"message"`
+	Data       interface{} `json:"data"`
+	CursorPagination
 }
 
-// buildResponse creates a response with localized message
-func buildResponse(statusCode int, responseType string, data interface{}, lang string) APIResponse {
+// CursorPagination is the page metadata of a CursorListResponse
+type CursorPagination struct {
+	NextCursor string `json:"nextCursor,omitempty"`
+	PrevCursor string `json:"prevCursor,omitempty"`
+	HasNext    bool   `json:"hasNext"`
+	HasPrev    bool   `json:"hasPrev"`
+	Limit      int    `json:"limit"`
+}
+
+// buildResponse creates a response with localized message. Params are
+// interpolated into the message, e.g. {minutes}; a "count" param selects
+// its plural form.
+func buildResponse(statusCode int, responseType string, data interface{}, lang string, params ...locale.Params) APIResponse {
 	return APIResponse{
 		StatusCode: statusCode,
 		Type:       responseType,
-		Message:    locale.Get(lang, responseType),
+		Message:    locale.T(lang, responseType, params...),
 		Data:       data,
 	}
 }
@@ -38,84 +89,99 @@ func buildResponse(statusCode int, responseType string, data interface{}, lang s
 // ========================
 
 // Default success response
-func Default(lang string) APIResponse {
-	return buildResponse(OK, TYPE_DEFAULT, nil, lang)
+func Default(lang string, params ...locale.Params) APIResponse {
+	return buildResponse(OK, TYPE_DEFAULT, nil, lang, params...)
 }
 
 // Details returns success with data
-func Details(data interface{}, lang string) APIResponse {
-	return buildResponse(OK, TYPE_DEFAULT, data, lang)
+func Details(data interface{}, lang string, params ...locale.Params) APIResponse {
+	return buildResponse(OK, TYPE_DEFAULT, data, lang, params...)
 }
 
 // List returns list response with data
-func List(data interface{}, lang string) APIResponse {
-	return buildResponse(OK, TYPE_DEFAULT, data, lang)
+func List(data interface{}, lang string, params ...locale.Params) APIResponse {
+	return buildResponse(OK, TYPE_DEFAULT, data, lang, params...)
 }
 
 // ListWithPagination returns list with pagination info
-func ListWithPagination(data interface{}, total int64, page, limit int, lang string) ListResponse {
+func ListWithPagination(data interface{}, pagination Pagination, lang string, params ...locale.Params) ListResponse {
 	return ListResponse{
 		StatusCode: OK,
 		Type:       TYPE_DEFAULT,
-		Message:    locale.Get(lang, TYPE_DEFAULT),
+		Message:    locale.T(lang, TYPE_DEFAULT, params...),
 		Data:       data,
-		Total:      total,
-		Page:       page,
-		Limit:      limit,
+		Pagination: pagination,
+	}
+}
+
+// ListWithCursor returns list with cursor pagination info
+func ListWithCursor(data interface{}, pagination CursorPagination, lang string, params ...locale.Params) CursorListResponse {
+	return CursorListResponse{
+		StatusCode:       OK,
+		Type:             TYPE_DEFAULT,
+		Message:          locale.T(lang, TYPE_DEFAULT, params...),
+		Data:             data,
+		CursorPagination: pagination,
 	}
 }
 
 // Login success response
-func Login(data interface{}, lang string) APIResponse {
-	return buildResponse(OK, TYPE_LOGIN, data, lang)
+func Login(data interface{}, lang string, params ...locale.Params) APIResponse {
+	return buildResponse(OK, TYPE_LOGIN, data, lang, params...)
 }
 
 // Signup success response
-func Signup(data interface{}, lang string) APIResponse {
-	return buildResponse(CREATED, TYPE_SIGNUP, data, lang)
+func Signup(data interface{}, lang string, params ...locale.Params) APIResponse {
+	return buildResponse(CREATED, TYPE_SIGNUP, data, lang, params...)
 }
 
 // Profile returns profile data
-func Profile(data interface{}, lang string) APIResponse {
-	return buildResponse(OK, TYPE_PROFILE, data, lang)
+func Profile(data interface{}, lang string, params ...locale.Params) APIResponse {
+	return buildResponse(OK, TYPE_PROFILE, data, lang, params...)
 }
 
 // UpdateProfile success
-func UpdateProfile(lang string) APIResponse {
-	return buildResponse(UPDATED, TYPE_UPDATE_PROFILE, nil, lang)
+func UpdateProfile(lang string, params ...locale.Params) APIResponse {
+	return buildResponse(UPDATED, TYPE_UPDATE_PROFILE, nil, lang, params...)
 }
 
 // Logout success
-func Logout(lang string) APIResponse {
-	return buildResponse(OK, TYPE_LOGOUT, nil, lang)
+func Logout(lang string, params ...locale.Params) APIResponse {
+	return buildResponse(OK, TYPE_LOGOUT, nil, lang, params...)
 }
 
 // ChangePassword success
-func ChangePassword(lang string) APIResponse {
-	return buildResponse(OK, TYPE_CHANGE_PASSWORD, nil, lang)
+func ChangePassword(lang string, params ...locale.Params) APIResponse {
+	return buildResponse(OK, TYPE_CHANGE_PASSWORD, nil, lang, params...)
 }
 
 // ResetPassword success
-func ResetPassword(lang string) APIResponse {
-	return buildResponse(OK, TYPE_RESET_PASSWORD, nil, lang)
+func ResetPassword(lang string, params ...locale.Params) APIResponse {
+	return buildResponse(OK, TYPE_RESET_PASSWORD, nil, lang, params...)
 }
 
 // SendOTP success
-func SendOTP(lang string) APIResponse {
-	return buildResponse(OK, TYPE_SEND_OTP, nil, lang)
+func SendOTP(lang string, params ...locale.Params) APIResponse {
+	return buildResponse(OK, TYPE_SEND_OTP, nil, lang, params...)
 }
 
 // VerifyOTP success with data
-func VerifyOTP(data interface{}, lang string) APIResponse {
-	return buildResponse(OK, TYPE_VERIFY_OTP, data, lang)
+func VerifyOTP(data interface{}, lang string, params ...locale.Params) APIResponse {
+	return buildResponse(OK, TYPE_VERIFY_OTP, data, lang, params...)
 }
 
 // ========================
 // ERROR RESPONSES
 // ========================
 
-// Error returns custom error response
-func Error(message string, lang string, statusCode ...int) APIResponse {
+// Error returns an error response with the message for a locale key,
+// e.g. "INVALID_REQUEST_BODY". Keys missing from the locales are sent as-is.
+func Error(key string, lang string, statusCode ...int) APIResponse {
+	return ErrorWithParams(key, nil, lang, statusCode...)
+}
+
+// ErrorWithParams is Error with parameters interpolated into the message
+func ErrorWithParams(key string, params locale.Params, lang string, statusCode ...int) APIResponse {
 	code := BAD_REQUEST
 	if len(statusCode) > 0 {
 		code = statusCode[0]
@@ -124,48 +190,83 @@ func Error(message string, lang string, statusCode ...int) APIResponse {
 	return APIResponse{
 		StatusCode: code,
 		Type:       TYPE_ERROR,
-		Message:    message,
+		Message:    locale.T(lang, key, params),
+	}
+}
+
+// FieldMessage is a localized validation error for one request field
+type FieldMessage struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists the invalid request fields. Each message comes from
+// the "validation.<rule>" locale key with {field} and {param}; a field is
+// shown by its "fields.<name>" label when the locale defines one.
+func ValidationError(errs []validator.FieldError, lang string) APIResponse {
+	fields := make([]FieldMessage, 0, len(errs))
+	for _, fe := range errs {
+		label, ok := locale.Lookup(lang, "fields."+fe.Field)
+		if !ok {
+			label = fe.Field
+		}
+		fields = append(fields, FieldMessage{
+			Field:   fe.Field,
+			Message: locale.T(lang, "validation."+fe.Rule, locale.Params{"field": label, "param": fe.Param}),
+		})
+	}
+	return buildResponse(BAD_REQUEST, TYPE_VALIDATION_ERROR, fields, lang)
+}
+
+// InvalidQuery rejects a query-string parameter. The message comes from the
+// "query.<reason>" locale key with {param} and {value}.
+func InvalidQuery(param, reason, value, lang string) APIResponse {
+	return APIResponse{
+		StatusCode: BAD_REQUEST,
+		Type:       TYPE_INVALID_QUERY,
+		Message:    locale.T(lang, "query."+reason, locale.Params{"param": param, "value": value}),
+		Data:       map[string]string{"param": param, "reason": reason, "value": value},
 	}
 }
 
 // UnauthorizedAccess error
-func UnauthorizedAccess(lang string) APIResponse {
-	return buildResponse(UNAUTHORIZED, TYPE_UNAUTHORIZED_ACCESS, nil, lang)
+func UnauthorizedAccess(lang string, params ...locale.Params) APIResponse {
+	return buildResponse(UNAUTHORIZED, TYPE_UNAUTHORIZED_ACCESS, nil, lang, params...)
 }
 
 // InternalServerError error
-func InternalServerError(lang string) APIResponse {
-	return buildResponse(INTERNAL_SERVER_ERROR, TYPE_INTERNAL_SERVER_ERROR_TYPE, nil, lang)
+func InternalServerError(lang string, params ...locale.Params) APIResponse {
+	return buildResponse(INTERNAL_SERVER_ERROR, TYPE_INTERNAL_SERVER_ERROR_TYPE, nil, lang, params...)
 }
 
 // BadToken error
-func BadToken(lang string) APIResponse {
-	return buildResponse(UNAUTHORIZED, TYPE_BAD_TOKEN, nil, lang)
+func BadToken(lang string, params ...locale.Params) APIResponse {
+	return buildResponse(UNAUTHORIZED, TYPE_BAD_TOKEN, nil, lang, params...)
 }
 
 // TokenExpired error
-func TokenExpired(lang string) APIResponse {
-	return buildResponse(UNAUTHORIZED, TYPE_TOKEN_EXPIRED, nil, lang)
+func TokenExpired(lang string, params ...locale.Params) APIResponse {
+	return buildResponse(UNAUTHORIZED, TYPE_TOKEN_EXPIRED, nil, lang, params...)
 }
 
 // SessionExpired error
-func SessionExpired(lang string) APIResponse {
-	return buildResponse(UNAUTHORIZED, TYPE_SESSION_EXPIRED, nil, lang)
+func SessionExpired(lang string, params ...locale.Params) APIResponse {
+	return buildResponse(UNAUTHORIZED, TYPE_SESSION_EXPIRED, nil, lang, params...)
 }
 
 // IncorrectPassword error
-func IncorrectPassword(lang string) APIResponse {
-	return buildResponse(BAD_REQUEST, TYPE_INCORRECT_PASSWORD, nil, lang)
+func IncorrectPassword(lang string, params ...locale.Params) APIResponse {
+	return buildResponse(BAD_REQUEST, TYPE_INCORRECT_PASSWORD, nil, lang, params...)
 }
 
 // EmailNotRegistered error
-func EmailNotRegistered(lang string) APIResponse {
-	return buildResponse(BAD_REQUEST, TYPE_EMAIL_NOT_REGISTERED, nil, lang)
+func EmailNotRegistered(lang string, params ...locale.Params) APIResponse {
+	return buildResponse(BAD_REQUEST, TYPE_EMAIL_NOT_REGISTERED, nil, lang, params...)
 }
 
 // EmailAlreadyExists error
-func EmailAlreadyExists(lang string) APIResponse {
-	return buildResponse(BAD_REQUEST, TYPE_EMAIL_ALREADY_EXIST, nil, lang)
+func EmailAlreadyExists(lang string, params ...locale.Params) APIResponse {
+	return buildResponse(BAD_REQUEST, TYPE_EMAIL_ALREADY_EXIST, nil, lang, params...)
 }
 
 // EmailNotVerified error
@@ -179,26 +280,131 @@ func EmailNotVerified(lang string, statusCode ...int) APIResponse {
 }
 
 // UserNotFound error
-func UserNotFound(lang string) APIResponse {
-	return buildResponse(BAD_REQUEST, TYPE_USER_NOT_FOUND, nil, lang)
+func UserNotFound(lang string, params ...locale.Params) APIResponse {
+	return buildResponse(NOT_FOUND, TYPE_USER_NOT_FOUND, nil, lang, params...)
 }
 
 // InvalidOTP error
-func InvalidOTP(lang string) APIResponse {
-	return buildResponse(BAD_REQUEST, TYPE_INVALID_OTP, nil, lang)
+func InvalidOTP(lang string, params ...locale.Params) APIResponse {
+	return buildResponse(BAD_REQUEST, TYPE_INVALID_OTP, nil, lang, params...)
 }
 
 // OTPExpired error
-func OTPExpired(lang string) APIResponse {
-	return buildResponse(BAD_REQUEST, TYPE_OTP_EXPIRED, nil, lang)
+func OTPExpired(lang string, params ...locale.Params) APIResponse {
+	return buildResponse(BAD_REQUEST, TYPE_OTP_EXPIRED, nil, lang, params...)
 }
 
 // BlockedUser error
-func BlockedUser(lang string) APIResponse {
-	return buildResponse(ACCESS_FORBIDDEN, TYPE_BLOCKED, nil, lang)
+func BlockedUser(lang string, params ...locale.Params) APIResponse {
+	return buildResponse(ACCESS_FORBIDDEN, TYPE_BLOCKED, nil, lang, params...)
 }
 
 // DeactivatedUser error
-func DeactivatedUser(lang string) APIResponse {
-	return buildResponse(ACCESS_FORBIDDEN, TYPE_DEACTIVATED, nil, lang)
+func DeactivatedUser(lang string, params ...locale.Params) APIResponse {
+	return buildResponse(ACCESS_FORBIDDEN, TYPE_DEACTIVATED, nil, lang, params...)
+}
+
+// AccessForbidden error
+func AccessForbidden(lang string, params ...locale.Params) APIResponse {
+	return buildResponse(ACCESS_FORBIDDEN, TYPE_ACCESS_FORBIDDEN, nil, lang, params...)
+}
+
+// AccountLocked error
+func AccountLocked(lang string, params ...locale.Params) APIResponse {
+	return buildResponse(TOO_MANY_REQUESTS, TYPE_ACCOUNT_LOCKED, nil, lang, params...)
+}
+
+// APIKeyCreated returns the new key together with its one-time secret
+func APIKeyCreated(data interface{}, lang string, params ...locale.Params) APIResponse {
+	return buildResponse(CREATED, TYPE_API_KEY_CREATED, data, lang, params...)
+}
+
+// APIKeyRotated returns the key together with its new one-time secret
+func APIKeyRotated(data interface{}, lang string, params ...locale.Params) APIResponse {
+	return buildResponse(OK, TYPE_API_KEY_ROTATED, data, lang, params...)
+}
+
+// APIKeyRevoked success
+func APIKeyRevoked(lang string, params ...locale.Params) APIResponse {
+	return buildResponse(OK, TYPE_API_KEY_REVOKED, nil, lang, params...)
+}
+
+// InvalidAPIKey error
+func InvalidAPIKey(lang string, params ...locale.Params) APIResponse {
+	return buildResponse(UNAUTHORIZED, TYPE_INVALID_API_KEY, nil, lang, params...)
+}
+
+// APIKeyExpired error
+func APIKeyExpired(lang string, params ...locale.Params) APIResponse {
+	return buildResponse(UNAUTHORIZED, TYPE_API_KEY_EXPIRED, nil, lang, params...)
+}
+
+// APIKeyNotFound error
+func APIKeyNotFound(lang string, params ...locale.Params) APIResponse {
+	return buildResponse(NOT_FOUND, TYPE_API_KEY_NOT_FOUND, nil, lang, params...)
+}
+
+// APIKeyAlreadyRevoked error
+func APIKeyAlreadyRevoked(lang string, params ...locale.Params) APIResponse {
+	return buildResponse(CONFLICT, TYPE_API_KEY_ALREADY_REVOKED, nil, lang, params...)
+}
+
+// TooManyRequests error
+func TooManyRequests(lang string, params ...locale.Params) APIResponse {
+	return buildResponse(TOO_MANY_REQUESTS, TYPE_TOO_MANY_REQUESTS, nil, lang, params...)
+}
+
+// CaptchaRequired error, returned for failed credentials once a CAPTCHA should be shown
+func CaptchaRequired(lang string, params ...locale.Params) APIResponse {
+	return buildResponse(UNAUTHORIZED, TYPE_CAPTCHA_REQUIRED, nil, lang, params...)
+}
+
+// LockoutCleared success
+func LockoutCleared(lang string, params ...locale.Params) APIResponse {
+	return buildResponse(OK, TYPE_LOCKOUT_CLEARED, nil, lang, params...)
+}
+
+// AppUpToDate success
+func AppUpToDate(data interface{}, lang string, params ...locale.Params) APIResponse {
+	return buildResponse(OK, TYPE_APP_UP_TO_DATE, data, lang, params...)
+}
+
+// UpdateAvailable tells the client a newer app version exists
+func UpdateAvailable(data interface{}, lang string, params ...locale.Params) APIResponse {
+	return buildResponse(OK, TYPE_UPDATE_AVAILABLE, data, lang, params...)
+}
+
+// UserDeleted success
+func UserDeleted(lang string, params ...locale.Params) APIResponse {
+	return buildResponse(OK, TYPE_USER_DELETED, nil, lang, params...)
+}
+
+// UserRestored success
+func UserRestored(lang string, params ...locale.Params) APIResponse {
+	return buildResponse(OK, TYPE_USER_RESTORED, nil, lang, params...)
+}
+
+// UserUpdated success
+func UserUpdated(data interface{}, lang string, params ...locale.Params) APIResponse {
+	return buildResponse(OK, TYPE_USER_UPDATED, data, lang, params...)
+}
+
+// VersionConflict rejects a write based on a stale copy of the document
+func VersionConflict(lang string, params ...locale.Params) APIResponse {
+	return buildResponse(CONFLICT, TYPE_VERSION_CONFLICT, nil, lang, params...)
+}
+
+// ForceUpdate tells the client its app version is below the supported minimum
+func ForceUpdate(data interface{}, lang string, params ...locale.Params) APIResponse {
+	return buildResponse(UPGRADE_REQUIRED, TYPE_FORCE_UPDATE, data, lang, params...)
+}
+
+// AppVersionUpdated success
+func AppVersionUpdated(data interface{}, lang string, params ...locale.Params) APIResponse {
+	return buildResponse(OK, TYPE_APP_VERSION_UPDATED, data, lang, params...)
+}
+
+// UnsupportedAPIVersion error, data lists the supported versions
+func UnsupportedAPIVersion(data interface{}, lang string, params ...locale.Params) APIResponse {
+	return buildResponse(BAD_REQUEST, TYPE_UNSUPPORTED_API_VERSION, data, lang, params...)
 }
@@ -0,0 +1,53 @@
+package config
+
+import "testing"
+
+func TestNewPagination(t *testing.T) {
+	tests := []struct {
+		name           string
+		page, limit    int
+		total          int64
+		hasNext        bool
+		wantTotal      int64 // -1 for null
+		wantTotalPages int   // -1 for null
+		wantNext       int   // 0 for null
+		wantPrev       int   // 0 for null
+	}{
+		{name: "first page", page: 1, limit: 10, total: 25, hasNext: true, wantTotal: 25, wantTotalPages: 3, wantNext: 2},
+		{name: "middle page", page: 2, limit: 10, total: 25, hasNext: true, wantTotal: 25, wantTotalPages: 3, wantNext: 3, wantPrev: 1},
+		{name: "last page", page: 3, limit: 10, total: 25, wantTotal: 25, wantTotalPages: 3, wantPrev: 2},
+		{name: "empty", page: 1, limit: 10, total: 0, wantTotal: 0, wantTotalPages: 0},
+		{name: "not counted", page: 2, limit: 10, total: -1, hasNext: true, wantTotal: -1, wantTotalPages: -1, wantNext: 3, wantPrev: 1},
+		{name: "no limit", page: 1, limit: 0, total: 5, wantTotal: -1, wantTotalPages: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewPagination(tt.page, tt.limit, tt.total, tt.hasNext)
+
+			if got.Page != tt.page || got.Limit != tt.limit || got.HasNext != tt.hasNext {
+				t.Errorf("page, limit, hasNext = %d, %d, %v, want %d, %d, %v", got.Page, got.Limit, got.HasNext, tt.page, tt.limit, tt.hasNext)
+			}
+			if total := valueOr(got.Total, -1); total != tt.wantTotal {
+				t.Errorf("Total = %d, want %d", total, tt.wantTotal)
+			}
+			if pages := valueOr(got.TotalPages, -1); pages != tt.wantTotalPages {
+				t.Errorf("TotalPages = %d, want %d", pages, tt.wantTotalPages)
+			}
+			if next := valueOr(got.NextPage, 0); next != tt.wantNext {
+				t.Errorf("NextPage = %d, want %d", next, tt.wantNext)
+			}
+			if prev := valueOr(got.PrevPage, 0); prev != tt.wantPrev {
+				t.Errorf("PrevPage = %d, want %d", prev, tt.wantPrev)
+			}
+		})
+	}
+}
+
+// valueOr dereferences p, or returns null when it is nil
+func valueOr[T any](p *T, null T) T {
+	if p == nil {
+		return null
+	}
+	return *p
+}
@@ -0,0 +1,246 @@
+package config
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/addixit1/fiber-boilerplate/internal/utils"
+)
+
+// ErrSecretNotFound is returned by a SecretProvider that has no value for a key
+var ErrSecretNotFound = errors.New("secret not found")
+
+// SecretProvider resolves secrets by their environment variable name
+// (e.g. "JWT_SECRET") from an external store.
+type SecretProvider interface {
+	Name() string
+	GetSecret(ctx context.Context, key string) (string, error)
+}
+
+// RotatingSecret holds a secret that can be replaced at runtime.
+// The previous value stays valid for a grace period after a rotation so
+// tokens signed just before it keep working.
+type RotatingSecret struct {
+	mu        sync.RWMutex
+	current   string
+	previous  string
+	rotatedAt time.Time
+}
+
+// Get returns the current value
+func (s *RotatingSecret) Get() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current
+}
+
+// Candidates returns the values accepted for verification, newest first
+func (s *RotatingSecret) Candidates() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	values := []string{s.current}
+	if s.previous != "" && time.Since(s.rotatedAt) < Current().Secrets.RotationGrace {
+		values = append(values, s.previous)
+	}
+	return values
+}
+
+// Matches compares value against the accepted candidates in constant time
+func (s *RotatingSecret) Matches(value string) bool {
+	matched := false
+	for _, candidate := range s.Candidates() {
+		if subtle.ConstantTimeCompare([]byte(candidate), []byte(value)) == 1 {
+			matched = true
+		}
+	}
+	return matched
+}
+
+// set replaces the value and reports whether it changed
+func (s *RotatingSecret) set(value string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if value == s.current {
+		return false
+	}
+	if s.current != "" {
+		s.previous = s.current
+		s.rotatedAt = time.Now()
+	}
+	s.current = value
+	return true
+}
+
+var (
+	// JWTKey is the JWT signing key; use it instead of Config.JWT.Secret
+	JWTKey = &RotatingSecret{}
+
+	// BasicAuthPassword is the Basic Auth password; use it instead of Config.BasicAuth.Password
+	BasicAuthPassword = &RotatingSecret{}
+
+	// rotating maps the env name of every reloadable secret to its holder
+	rotating = map[string]*RotatingSecret{
+		"JWT_SECRET":          JWTKey,
+		"BASIC_AUTH_PASSWORD": BasicAuthPassword,
+	}
+
+	secretProvider SecretProvider
+)
+
+// readSecretFile reads a *_FILE secret, dropping the trailing newline editors add
+func readSecretFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// lookupEnv returns the value of key, falling back to the file named by key_FILE
+func lookupEnv(key string) (string, bool, error) {
+	value, hasValue := os.LookupEnv(key)
+	file, hasFile := os.LookupEnv(key + "_FILE")
+	hasValue = hasValue && value != ""
+	hasFile = hasFile && file != ""
+
+	switch {
+	case hasValue && hasFile:
+		return "", false, fmt.Errorf("set either %s or %s_FILE, not both", key, key)
+	case hasFile:
+		value, err := readSecretFile(file)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to read %s_FILE: %w", key, err)
+		}
+		return value, true, nil
+	default:
+		return value, hasValue, nil
+	}
+}
+
+// newSecretProvider builds the provider selected by cfg.Secrets
+func newSecretProvider(cfg SecretsConfig) (SecretProvider, error) {
+	switch cfg.Provider {
+	case "file":
+		key, err := DecodeSecretsKey(cfg.Key)
+		if err != nil {
+			return nil, err
+		}
+		return NewEncryptedFileProvider(cfg.File, key), nil
+	default:
+		return nil, nil
+	}
+}
+
+// applySecrets fills every `secret` field that was not set through the
+// environment from the secret provider
+func applySecrets(cfg *AppConfig, provider SecretProvider) Errors {
+	if provider == nil {
+		return nil
+	}
+
+	var errs Errors
+	for _, f := range fields(cfg) {
+		if !f.secret || f.env == "" {
+			continue
+		}
+		if _, ok, _ := lookupEnv(f.env); ok {
+			continue
+		}
+
+		value, err := provider.GetSecret(context.Background(), f.env)
+		if errors.Is(err, ErrSecretNotFound) {
+			continue
+		}
+		if err != nil {
+			errs = append(errs, FieldError{Path: f.path, Env: f.env, Message: provider.Name() + ": " + err.Error()})
+			continue
+		}
+		f.value.SetString(value)
+	}
+	return errs
+}
+
+// seedSecrets copies the loaded secrets into their rotating holders
+func seedSecrets(cfg *AppConfig) {
+	JWTKey.set(cfg.JWT.Secret)
+	BasicAuthPassword.set(cfg.BasicAuth.Password)
+}
+
+// resolveSecret re-reads a reloadable secret from its *_FILE or the provider.
+// Plain environment variables cannot change at runtime and are skipped.
+func resolveSecret(ctx context.Context, key string) (string, bool, error) {
+	if file := os.Getenv(key + "_FILE"); file != "" {
+		value, err := readSecretFile(file)
+		return value, err == nil, err
+	}
+	if os.Getenv(key) != "" || secretProvider == nil {
+		return "", false, nil
+	}
+
+	value, err := secretProvider.GetSecret(ctx, key)
+	if errors.Is(err, ErrSecretNotFound) {
+		return "", false, nil
+	}
+	return value, err == nil, err
+}
+
+// ReloadSecrets re-resolves every reloadable secret and swaps in changed values
+func ReloadSecrets(ctx context.Context) {
+	cfg := Config
+	rules := map[string]field{}
+	for _, f := range fields(&cfg) {
+		rules[f.env] = f
+	}
+
+	for key, holder := range rotating {
+		value, ok, err := resolveSecret(ctx, key)
+		if err != nil {
+			utils.LogError("Failed to reload secret " + key + ": " + err.Error())
+			continue
+		}
+		if !ok {
+			continue
+		}
+
+		f := rules[key]
+		f.value.SetString(value)
+		if msg := checkRules(f); msg != "" {
+			utils.LogError("Rejected reloaded secret " + key + ": " + msg)
+			continue
+		}
+
+		if holder.set(value) {
+			utils.LogInfo("Secret " + key + " rotated")
+		}
+	}
+}
+
+// WatchSecrets reloads secrets every Secrets.ReloadInterval until ctx is done
+func WatchSecrets(ctx context.Context) {
+	interval := Config.Secrets.ReloadInterval
+	if interval <= 0 {
+		return
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				ReloadSecrets(ctx)
+			}
+		}
+	}()
+}
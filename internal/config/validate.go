@@ -0,0 +1,178 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldError describes a single invalid configuration value
+type FieldError struct {
+	Path    string
+	Env     string
+	Message string
+}
+
+func (e FieldError) String() string {
+	if e.Env != "" {
+		return fmt.Sprintf("%s (%s): %s", e.Path, e.Env, e.Message)
+	}
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// Errors aggregates every configuration problem found during Load
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid configuration (%d problems):", len(e))
+	for _, fe := range e {
+		b.WriteString("\n  - ")
+		b.WriteString(fe.String())
+	}
+	return b.String()
+}
+
+// validate checks every `validate` tag in cfg. Fields that already failed
+// to parse are skipped so each problem is reported once.
+func validate(cfg *AppConfig, previous Errors) Errors {
+	failed := map[string]bool{}
+	for _, fe := range previous {
+		failed[fe.Path] = true
+	}
+
+	var errs Errors
+	for _, f := range fields(cfg) {
+		if failed[f.path] {
+			continue
+		}
+		if msg := checkRules(f); msg != "" {
+			errs = append(errs, FieldError{Path: f.path, Env: f.env, Message: msg})
+		}
+	}
+
+	basicAuth := cfg.BasicAuth
+	if basicAuth.Username == "" && len(basicAuth.Users) == 0 && basicAuth.UsersFile == "" {
+		errs = append(errs, FieldError{Path: "basicAuth", Env: "BASIC_AUTH_USERNAME", Message: "at least one Basic Auth user is required"})
+	}
+	if basicAuth.Username != "" && basicAuth.Password == "" && !failed["basicAuth.password"] {
+		errs = append(errs, FieldError{Path: "basicAuth.password", Env: "BASIC_AUTH_PASSWORD", Message: "is required when basicAuth.username is set"})
+	}
+
+	for name, group := range cfg.RateLimit.Groups {
+		errs = append(errs, validateRateLimitGroup(name, group)...)
+	}
+	for version, sunset := range cfg.APIVersions.Sunset {
+		if _, err := time.Parse(time.DateOnly, sunset); err != nil {
+			errs = append(errs, FieldError{Path: "apiVersions.sunset." + version, Env: "API_VERSION_SUNSET", Message: fmt.Sprintf("invalid date %q, expected YYYY-MM-DD", sunset)})
+		}
+	}
+	return errs
+}
+
+// validateRateLimitGroup checks an entry of rateLimit.groups, whose
+// empty values inherit from the top-level rate limit
+func validateRateLimitGroup(name string, group RateLimitGroup) Errors {
+	var out []field
+	walk(reflect.ValueOf(&group).Elem(), "rateLimit.groups."+name, &out)
+
+	var errs Errors
+	for _, f := range out {
+		if msg := checkRules(f); msg != "" {
+			errs = append(errs, FieldError{Path: f.path, Message: msg})
+		}
+	}
+
+	path := "rateLimit.groups." + name
+	if group.Algorithm != "" {
+		if msg := checkRule(reflect.ValueOf(group.Algorithm), "oneof=sliding_window token_bucket"); msg != "" {
+			errs = append(errs, FieldError{Path: path + ".algorithm", Message: msg})
+		}
+	}
+	if group.Key != "" {
+		if msg := checkRule(reflect.ValueOf(group.Key), "oneof=ip user api_key"); msg != "" {
+			errs = append(errs, FieldError{Path: path + ".key", Message: msg})
+		}
+	}
+	return errs
+}
+
+// checkRules returns the first `validate` rule f violates, or ""
+func checkRules(f field) string {
+	if f.validate == "" {
+		return ""
+	}
+	for _, rule := range strings.Split(f.validate, ",") {
+		if msg := checkRule(f.value, rule); msg != "" {
+			return msg
+		}
+	}
+	return ""
+}
+
+// checkRule returns a message describing why v violates rule, or "" when it passes
+func checkRule(v reflect.Value, rule string) string {
+	name, arg, _ := strings.Cut(strings.TrimSpace(rule), "=")
+
+	switch name {
+	case "required":
+		if v.IsZero() || ((v.Kind() == reflect.Slice || v.Kind() == reflect.Map) && v.Len() == 0) {
+			return "is required"
+		}
+	case "oneof":
+		options := strings.Fields(arg)
+		actual := fmt.Sprint(v.Interface())
+		for _, option := range options {
+			if actual == option {
+				return ""
+			}
+		}
+		return fmt.Sprintf("must be one of [%s], got %q", strings.Join(options, " "), actual)
+	case "min", "max":
+		return checkBound(v, name, arg)
+	}
+	return ""
+}
+
+// checkBound validates numeric values, durations and string lengths against a bound
+func checkBound(v reflect.Value, name, arg string) string {
+	var actual, bound float64
+	unit := ""
+
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(arg)
+		if err != nil {
+			return "invalid rule " + name + "=" + arg
+		}
+		actual, bound = float64(v.Int()), float64(d)
+	case v.Kind() == reflect.String:
+		// Empty optional strings are handled by "required"
+		if v.Len() == 0 {
+			return ""
+		}
+		n, _ := strconv.Atoi(arg)
+		actual, bound, unit = float64(v.Len()), float64(n), " characters"
+	case v.CanInt():
+		n, _ := strconv.ParseFloat(arg, 64)
+		actual, bound = float64(v.Int()), n
+	case v.CanUint():
+		n, _ := strconv.ParseFloat(arg, 64)
+		actual, bound = float64(v.Uint()), n
+	case v.CanFloat():
+		n, _ := strconv.ParseFloat(arg, 64)
+		actual, bound = v.Float(), n
+	default:
+		return ""
+	}
+
+	if name == "min" && actual < bound {
+		return fmt.Sprintf("must be at least %s%s", arg, unit)
+	}
+	if name == "max" && actual > bound {
+		return fmt.Sprintf("must be at most %s%s", arg, unit)
+	}
+	return ""
+}
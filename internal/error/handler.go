@@ -15,7 +15,7 @@ func Handler(c *fiber.Ctx, err error) error {
 	}
 
 	// PROD MODE (safe)
-	if config.Config.Env == "production" {
+	if config.Config.IsProduction() {
 		return c.Status(code).JSON(fiber.Map{
 			"message": eMessage(err),
 		})
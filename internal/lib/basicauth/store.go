@@ -0,0 +1,194 @@
+package basicauth
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/addixit1/fiber-boilerplate/internal/config"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ScopeAll grants access to every route
+const ScopeAll = "*"
+
+// User is an authenticated Basic Auth user
+type User struct {
+	Username string
+	Scopes   []string
+
+	// hash is the bcrypt hash; empty for the environment user, whose
+	// plaintext password is compared through config.BasicAuthPassword
+	hash []byte
+}
+
+// HasScope reports whether the user may access a route requiring any of scopes.
+// Routes without scopes are open to every user.
+func (u *User) HasScope(scopes ...string) bool {
+	if len(scopes) == 0 {
+		return true
+	}
+	for _, granted := range u.Scopes {
+		if granted == ScopeAll {
+			return true
+		}
+		for _, required := range scopes {
+			if granted == required {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+var (
+	users = map[string]*User{}
+	mu    sync.RWMutex
+
+	// dummyHash is compared against for unknown users so a lookup miss
+	// takes as long as a wrong password
+	dummyHash, _ = bcrypt.GenerateFromPassword([]byte("basic-auth-timing-guard"), bcrypt.DefaultCost)
+)
+
+// Load builds the user list from the config and the htpasswd-style users
+// file, replacing the current users only when everything is valid
+func Load() error {
+	cfg := config.Config.BasicAuth
+	loaded := map[string]*User{}
+
+	if cfg.Username != "" {
+		user := &User{Username: cfg.Username, Scopes: cfg.Scopes}
+		if isBcryptHash(cfg.Password) {
+			user.hash = []byte(cfg.Password)
+		}
+		loaded[user.Username] = user
+	}
+
+	for i, entry := range cfg.Users {
+		user, err := newHashedUser(entry.Username, entry.PasswordHash, entry.Scopes)
+		if err != nil {
+			return fmt.Errorf("basicAuth.users[%d]: %w", i, err)
+		}
+		if err := add(loaded, user); err != nil {
+			return fmt.Errorf("basicAuth.users[%d]: %w", i, err)
+		}
+	}
+
+	if cfg.UsersFile != "" {
+		fileUsers, err := readUsersFile(cfg.UsersFile)
+		if err != nil {
+			return err
+		}
+		for _, user := range fileUsers {
+			if err := add(loaded, user); err != nil {
+				return fmt.Errorf("%s: %w", cfg.UsersFile, err)
+			}
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	users = loaded
+	return nil
+}
+
+// Authenticate checks a username and password in constant time with
+// respect to whether the user exists
+func Authenticate(username, password string) (*User, bool) {
+	mu.RLock()
+	user, ok := users[username]
+	mu.RUnlock()
+
+	if !ok {
+		_ = bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return nil, false
+	}
+
+	if user.hash == nil {
+		// Environment user with a plaintext (possibly rotated) password
+		if !config.BasicAuthPassword.Matches(password) {
+			return nil, false
+		}
+		return user, true
+	}
+
+	if bcrypt.CompareHashAndPassword(user.hash, []byte(password)) != nil {
+		return nil, false
+	}
+	return user, true
+}
+
+// readUsersFile parses an htpasswd-style file with bcrypt hashes:
+//
+//	# comment
+//	username:$2y$10$...:scope1,scope2
+//
+// The scopes column is optional and defaults to no scopes.
+func readUsersFile(path string) ([]*User, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open Basic Auth users file: %w", err)
+	}
+	defer file.Close()
+
+	var out []*User
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(text, ":", 3)
+		if len(parts) < 2 {
+			return nil, fmt.Errorf("%s:%d: expected username:hash[:scopes]", path, line)
+		}
+
+		var scopes []string
+		if len(parts) == 3 {
+			for _, scope := range strings.Split(parts[2], ",") {
+				if scope = strings.TrimSpace(scope); scope != "" {
+					scopes = append(scopes, scope)
+				}
+			}
+		}
+
+		user, err := newHashedUser(parts[0], parts[1], scopes)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		out = append(out, user)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read Basic Auth users file: %w", err)
+	}
+	return out, nil
+}
+
+func newHashedUser(username, hash string, scopes []string) (*User, error) {
+	if username == "" {
+		return nil, fmt.Errorf("username is required")
+	}
+	if !isBcryptHash(hash) {
+		return nil, fmt.Errorf("user %q: password must be a bcrypt hash", username)
+	}
+	if _, err := bcrypt.Cost([]byte(hash)); err != nil {
+		return nil, fmt.Errorf("user %q: invalid bcrypt hash: %w", username, err)
+	}
+	return &User{Username: username, Scopes: scopes, hash: []byte(hash)}, nil
+}
+
+func add(users map[string]*User, user *User) error {
+	if _, exists := users[user.Username]; exists {
+		return fmt.Errorf("duplicate user %q", user.Username)
+	}
+	users[user.Username] = user
+	return nil
+}
+
+func isBcryptHash(value string) bool {
+	return strings.HasPrefix(value, "$2a$") || strings.HasPrefix(value, "$2b$") || strings.HasPrefix(value, "$2y$")
+}
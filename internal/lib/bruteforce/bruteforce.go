@@ -0,0 +1,228 @@
+package bruteforce
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/addixit1/fiber-boilerplate/internal/config"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/redis"
+	"github.com/addixit1/fiber-boilerplate/internal/utils/errortracker"
+)
+
+// Kind is what a failure counter or lockout is tracked by
+type Kind string
+
+const (
+	KindAccount Kind = "account"
+	KindIP      Kind = "ip"
+)
+
+const keyPrefix = "bruteforce:"
+
+// ErrInvalidKind is returned by Clear for an unknown kind
+var ErrInvalidKind = errors.New("kind must be account or ip")
+
+// Status describes the protection state of a login attempt
+type Status struct {
+	// RetryAfter is how long the account or IP stays locked; 0 when not locked
+	RetryAfter time.Duration
+
+	// CaptchaRequired is set once the account has failed CaptchaAfter times
+	CaptchaRequired bool
+
+	// Delay is how long the account must wait after a failure before its
+	// next attempt is checked; attempts made sooner are rejected
+	Delay time.Duration
+}
+
+// Locked reports whether the attempt must be rejected without checking credentials
+func (s Status) Locked() bool {
+	return s.RetryAfter > 0
+}
+
+// Throttled reports whether the attempt came too soon after a failure
+func (s Status) Throttled() bool {
+	return s.Delay > 0
+}
+
+// Lockout is an active lockout, as listed for admins
+type Lockout struct {
+	Scope      string `json:"scope"`
+	Kind       Kind   `json:"kind"`
+	Subject    string `json:"subject"`
+	RetryAfter int64  `json:"retry_after"` // seconds
+}
+
+// Check returns whether account or ip is locked out or throttled in scope
+// (e.g. "basicauth"). Accounts are tracked per client IP, so failures from
+// one IP never lock the account out for the others.
+// Checks are skipped (fail open) when Redis is unavailable.
+func Check(scope, account, ip string) Status {
+	var status Status
+	for kind, subject := range subjects(account, ip) {
+		ttl, err := redis.TTL(lockKey(scope, kind, subject))
+		if err != nil {
+			errortracker.Track(errortracker.LayerMiddleware, "Failed to read lockout", err)
+			return Status{}
+		}
+		status.RetryAfter = max(status.RetryAfter, ttl)
+
+		if kind == KindAccount {
+			wait, err := redis.TTL(waitKey(scope, subject))
+			if err != nil {
+				errortracker.Track(errortracker.LayerMiddleware, "Failed to read failure delay", err)
+				return Status{}
+			}
+			status.Delay = wait
+		}
+	}
+	return status
+}
+
+// Fail counts a failed attempt for account and ip, locking either once it
+// reaches its limit within AttemptWindow. Below the limit, the account's
+// next attempt is held off for the returned Delay.
+func Fail(scope, account, ip string) Status {
+	cfg := config.Current().BruteForce
+	limits := map[Kind]int{KindAccount: cfg.MaxAccountAttempts, KindIP: cfg.MaxIPAttempts}
+
+	var status Status
+	for kind, subject := range subjects(account, ip) {
+		failures, err := redis.IncrWithTTL(failuresKey(scope, kind, subject), cfg.AttemptWindow)
+		if err != nil {
+			errortracker.Track(errortracker.LayerMiddleware, "Failed to record failed attempt", err)
+			return Status{}
+		}
+
+		if kind == KindAccount {
+			status.CaptchaRequired = cfg.CaptchaAfter > 0 && failures >= int64(cfg.CaptchaAfter)
+		}
+		if failures < int64(limits[kind]) {
+			if kind == KindAccount {
+				status.Delay = hold(scope, subject, delay(cfg, failures))
+			}
+			continue
+		}
+
+		if err := redis.Set(lockKey(scope, kind, subject), "1", cfg.LockoutDuration); err != nil {
+			errortracker.Track(errortracker.LayerMiddleware, "Failed to lock out "+string(kind), err)
+			continue
+		}
+		_ = redis.Del(failuresKey(scope, kind, subject))
+		status.RetryAfter = cfg.LockoutDuration
+	}
+	if status.Locked() {
+		status.Delay = 0
+	}
+	return status
+}
+
+// hold makes the account wait d before its next attempt
+func hold(scope, subject string, d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	if err := redis.Set(waitKey(scope, subject), "1", d); err != nil {
+		errortracker.Track(errortracker.LayerMiddleware, "Failed to delay next attempt", err)
+		return 0
+	}
+	return d
+}
+
+// Succeed clears the account's failure counter from ip after a successful login.
+// The IP counter is kept so one valid account cannot reset a credential spray.
+func Succeed(scope, account, ip string) {
+	if err := redis.Del(failuresKey(scope, KindAccount, AccountSubject(account, ip))); err != nil {
+		errortracker.Track(errortracker.LayerMiddleware, "Failed to reset failed attempts", err)
+	}
+}
+
+// List returns every active lockout
+func List(ctx context.Context) ([]Lockout, error) {
+	lockouts := []Lockout{}
+	iter := redis.Client.Scan(ctx, 0, keyPrefix+"*:lock:*", 100).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		scope, kind, subject, ok := parseLockKey(key)
+		if !ok {
+			continue
+		}
+		ttl, err := redis.TTL(key)
+		if err != nil {
+			return nil, err
+		}
+		if ttl > 0 {
+			lockouts = append(lockouts, Lockout{Scope: scope, Kind: kind, Subject: subject, RetryAfter: int64(math.Ceil(ttl.Seconds()))})
+		}
+	}
+	return lockouts, iter.Err()
+}
+
+// Clear removes the lockout and failure counter of an account or IP in
+// scope. Account subjects are as listed, see AccountSubject.
+func Clear(scope string, kind Kind, subject string) error {
+	switch kind {
+	case KindAccount:
+		return redis.Del(lockKey(scope, kind, subject), failuresKey(scope, kind, subject), waitKey(scope, subject))
+	case KindIP:
+		return redis.Del(lockKey(scope, kind, subject), failuresKey(scope, kind, subject))
+	}
+	return ErrInvalidKind
+}
+
+// AccountSubject is the subject an account is tracked by: the account
+// together with the client IP, e.g. "ops@203.0.113.7"
+func AccountSubject(account, ip string) string {
+	if ip == "" {
+		return account
+	}
+	return account + "@" + ip
+}
+
+// delay grows exponentially from DelayBase with each failure, up to MaxDelay
+func delay(cfg config.BruteForceConfig, failures int64) time.Duration {
+	if cfg.DelayBase <= 0 || failures < 1 {
+		return 0
+	}
+	d := float64(cfg.DelayBase) * math.Pow(2, float64(failures-1))
+	if d > float64(cfg.MaxDelay) {
+		return cfg.MaxDelay
+	}
+	return time.Duration(d)
+}
+
+// subjects lists the non-empty identities an attempt is tracked by
+func subjects(account, ip string) map[Kind]string {
+	out := map[Kind]string{}
+	if account != "" {
+		out[KindAccount] = AccountSubject(account, ip)
+	}
+	if ip != "" {
+		out[KindIP] = ip
+	}
+	return out
+}
+
+func failuresKey(scope string, kind Kind, subject string) string {
+	return keyPrefix + scope + ":failures:" + string(kind) + ":" + subject
+}
+
+func lockKey(scope string, kind Kind, subject string) string {
+	return keyPrefix + scope + ":lock:" + string(kind) + ":" + subject
+}
+
+func waitKey(scope string, subject string) string {
+	return keyPrefix + scope + ":wait:" + string(KindAccount) + ":" + subject
+}
+
+// parseLockKey splits bruteforce:<scope>:lock:<kind>:<subject>
+func parseLockKey(key string) (string, Kind, string, bool) {
+	parts := strings.SplitN(strings.TrimPrefix(key, keyPrefix), ":", 4)
+	if len(parts) != 4 || parts[1] != "lock" {
+		return "", "", "", false
+	}
+	return parts[0], Kind(parts[2]), parts[3], true
+}
@@ -24,16 +24,18 @@ func ConnectMongo() {
 	)
 
 	// Debug log before connection
-	if config.Config.DebugStatus == "true" {
-		log.Println(colorCyan + "[MongoDB Debug] Attempting to connect to database: " + config.Config.MongoDbName + colorReset)
-		log.Println(colorCyan + "[MongoDB Debug] Connection URI: " + config.Config.MongoURI + colorReset)
+	if config.Config.Mongo.Debug {
+		log.Println(colorCyan + "[MongoDB Debug] Attempting to connect to database: " + config.Config.Mongo.DbName + colorReset)
+		log.Println(colorCyan + "[MongoDB Debug] Connection URI: " + config.Config.Mongo.URI + colorReset)
 	}
 
 	// Prepare client options
-	clientOpts := options.Client().ApplyURI(config.Config.MongoURI)
+	clientOpts := options.Client().
+		ApplyURI(config.Config.Mongo.URI).
+		SetConnectTimeout(config.Config.Mongo.ConnectTimeout)
 
 	// Enable query logging if debug mode is on
-	if config.Config.DebugStatus == "true" {
+	if config.Config.Mongo.Debug {
 		monitor := &event.CommandMonitor{
 			Started: func(_ context.Context, evt *event.CommandStartedEvent) {
 				log.Printf(colorYellow+"[MongoDB Query] Command: %s | DB: %s"+colorReset+"\n", evt.CommandName, evt.DatabaseName)
@@ -52,8 +54,8 @@ func ConnectMongo() {
 	}
 
 	err := mgm.SetDefaultConfig(
-		nil,
-		config.Config.MongoDbName,
+		&mgm.Config{CtxTimeout: config.Config.Mongo.OperationTimeout},
+		config.Config.Mongo.DbName,
 		clientOpts,
 	)
 
@@ -62,15 +64,15 @@ func ConnectMongo() {
 	}
 
 	// Debug log after successful connection
-	if config.Config.DebugStatus == "true" {
+	if config.Config.Mongo.Debug {
 		log.Println(colorGreen + "[MongoDB Debug] Successfully connected to database" + colorReset)
-		log.Println(colorGreen + "[MongoDB Debug] Database name: " + config.Config.MongoDbName + colorReset)
+		log.Println(colorGreen + "[MongoDB Debug] Database name: " + config.Config.Mongo.DbName + colorReset)
 	}
 
 	debugStatus := ""
-	if config.Config.DebugStatus == "true" {
+	if config.Config.Mongo.Debug {
 		debugStatus = " (Debug: ON)"
 	}
 
-	utils.LogDatabase("MongoDB connected to " + config.Config.MongoDbName + debugStatus)
+	utils.LogDatabase("MongoDB connected to " + config.Config.Mongo.DbName + debugStatus)
 }
@@ -0,0 +1,34 @@
+package etag
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalid is returned for If-Match values this API never issues, such
+// as weak tags or lists of tags
+var ErrInvalid = errors.New("invalid entity tag")
+
+// Format returns the strong entity tag of a document version, e.g. `"3"`
+func Format(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// ParseIfMatch reads an If-Match header value. ok is false when the header
+// is empty or "*", which lets the write go ahead whatever the version.
+func ParseIfMatch(header string) (version int64, ok bool, err error) {
+	header = strings.TrimSpace(header)
+	if header == "" || header == "*" {
+		return 0, false, nil
+	}
+
+	if len(header) < 2 || header[0] != '"' || header[len(header)-1] != '"' {
+		return 0, false, ErrInvalid
+	}
+	version, err = strconv.ParseInt(header[1:len(header)-1], 10, 64)
+	if err != nil || version < 0 {
+		return 0, false, ErrInvalid
+	}
+	return version, true, nil
+}
@@ -0,0 +1,152 @@
+package hotreload
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/addixit1/fiber-boilerplate/internal/config"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/basicauth"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/locale"
+	"github.com/addixit1/fiber-boilerplate/internal/utils"
+)
+
+// Watch reloads the locale bundles, the runtime-safe config values and the
+// Basic Auth users file when they change on disk or the process receives
+// SIGHUP. Files are polled every HotReload.Interval; it returns immediately
+// when disabled.
+func Watch(ctx context.Context) {
+	if !config.Config.HotReload.Enabled {
+		return
+	}
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	go func() {
+		defer signal.Stop(hup)
+
+		ticker := time.NewTicker(config.Config.HotReload.Interval)
+		defer ticker.Stop()
+
+		configSig := fingerprint(configFiles())
+		localeSig := fingerprint(localeFiles())
+		usersSig := fingerprint(usersFiles())
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+
+			case <-hup:
+				utils.LogInfo("SIGHUP received, reloading configuration and locales")
+				reloadConfig()
+				reloadLocales()
+				reloadUsers()
+				configSig = fingerprint(configFiles())
+				localeSig = fingerprint(localeFiles())
+				usersSig = fingerprint(usersFiles())
+
+			case <-ticker.C:
+				if sig := fingerprint(configFiles()); sig != configSig {
+					configSig = sig
+					reloadConfig()
+				}
+				if sig := fingerprint(localeFiles()); sig != localeSig {
+					localeSig = sig
+					reloadLocales()
+				}
+				if sig := fingerprint(usersFiles()); sig != usersSig {
+					usersSig = sig
+					reloadUsers()
+				}
+			}
+		}
+	}()
+}
+
+// reloadConfig applies runtime-safe config changes and logs the outcome
+func reloadConfig() {
+	changes, err := config.Reload()
+	if err != nil {
+		utils.LogError("Config reload rejected, keeping current values: " + err.Error())
+		return
+	}
+	if len(changes) == 0 {
+		utils.LogInfo("Config reloaded, no changes")
+		return
+	}
+	for _, change := range changes {
+		if change.Applied {
+			utils.LogSuccess("Config reloaded: " + change.String())
+		} else {
+			utils.LogWarning("Config reload: " + change.String())
+		}
+	}
+}
+
+// reloadLocales swaps in the locale bundles and logs the outcome
+func reloadLocales() {
+	changes, err := locale.Reload()
+	if err != nil {
+		utils.LogError("Locale reload rejected, keeping current messages: " + err.Error())
+		return
+	}
+	if len(changes) == 0 {
+		utils.LogInfo("Locales reloaded, no changes")
+		return
+	}
+	utils.LogSuccess("Locales reloaded: " + strings.Join(changes, "; "))
+}
+
+// reloadUsers re-reads the Basic Auth users file
+func reloadUsers() {
+	if err := basicauth.Load(); err != nil {
+		utils.LogError("Basic Auth users reload rejected, keeping current users: " + err.Error())
+		return
+	}
+	utils.LogSuccess("Basic Auth users reloaded")
+}
+
+// configFiles lists the config file being watched. Environment variables
+// (including .env) are fixed for the lifetime of the process.
+func configFiles() []string {
+	if file := config.File(); file != "" {
+		return []string{file}
+	}
+	return nil
+}
+
+// localeFiles lists the locale bundles on disk
+func localeFiles() []string {
+	return locale.Files()
+}
+
+// usersFiles lists the Basic Auth users file, if one is configured
+func usersFiles() []string {
+	if file := config.Config.BasicAuth.UsersFile; file != "" {
+		return []string{file}
+	}
+	return nil
+}
+
+// fingerprint summarises the name, size and modification time of files
+// so any edit, addition or removal changes the result
+func fingerprint(files []string) string {
+	sort.Strings(files)
+
+	var b strings.Builder
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			continue
+		}
+		fmt.Fprintf(&b, "%s:%d:%d;", file, info.Size(), info.ModTime().UnixNano())
+	}
+	return b.String()
+}
@@ -0,0 +1,227 @@
+package locale
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/addixit1/fiber-boilerplate/internal/utils"
+)
+
+// Report lists the problems found in one locale bundle
+type Report struct {
+	Lang string
+
+	// Missing keys are defined by the default language (or referenced by
+	// the code, for the default language itself) but not by Lang
+	Missing []string
+
+	// Extra keys are defined by Lang but not by the default language
+	Extra []string
+
+	// Unused keys are defined by the default language but never referenced.
+	// Only filled when Check is given the referenced keys.
+	Unused []string
+
+	// Placeholders lists keys whose {params} differ from the default language
+	Placeholders []PlaceholderMismatch
+}
+
+// PlaceholderMismatch is a key whose translation uses different {params}
+type PlaceholderMismatch struct {
+	Key      string
+	Expected []string
+	Got      []string
+}
+
+// OK reports whether the bundle has no problems
+func (r Report) OK() bool {
+	return len(r.Missing)+len(r.Extra)+len(r.Unused)+len(r.Placeholders) == 0
+}
+
+// Problems returns one line per problem
+func (r Report) Problems() []string {
+	var lines []string
+	for _, key := range r.Missing {
+		lines = append(lines, r.Lang+": missing "+key)
+	}
+	for _, key := range r.Extra {
+		lines = append(lines, r.Lang+": extra "+key)
+	}
+	for _, key := range r.Unused {
+		lines = append(lines, r.Lang+": unused "+key)
+	}
+	for _, m := range r.Placeholders {
+		lines = append(lines, fmt.Sprintf("%s: %s has placeholders %v, expected %v", r.Lang, m.Key, m.Got, m.Expected))
+	}
+	return lines
+}
+
+// Check compares every loaded bundle against the default language. Plural
+// forms are compared as one message since languages use different
+// categories. When referenced is given (keys used by the code; entries
+// ending in "." are prefixes such as "validation."), the default language
+// is also checked for keys the code needs but no bundle defines, and for
+// keys nothing uses.
+func Check(referenced ...string) []Report {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	base := groupPlurals(messages[defaultLang])
+	reports := []Report{}
+
+	if referenced != nil {
+		report := Report{Lang: defaultLang}
+		for _, key := range referenced {
+			if strings.HasSuffix(key, ".") {
+				continue
+			}
+			if _, ok := base[key]; !ok {
+				report.Missing = append(report.Missing, key)
+			}
+		}
+		for key := range base {
+			if !isReferenced(key, referenced) {
+				report.Unused = append(report.Unused, key)
+			}
+		}
+		reports = append(reports, report.sorted())
+	}
+
+	langs := make([]string, 0, len(messages))
+	for lang := range messages {
+		if lang != defaultLang {
+			langs = append(langs, lang)
+		}
+	}
+	sort.Strings(langs)
+
+	for _, lang := range langs {
+		report := Report{Lang: lang}
+		bundle := groupPlurals(messages[lang])
+		for key, expected := range base {
+			got, ok := bundle[key]
+			if !ok {
+				report.Missing = append(report.Missing, key)
+				continue
+			}
+			if !equalStrings(expected, got) {
+				report.Placeholders = append(report.Placeholders, PlaceholderMismatch{Key: key, Expected: expected, Got: got})
+			}
+		}
+		for key := range bundle {
+			if _, ok := base[key]; !ok {
+				report.Extra = append(report.Extra, key)
+			}
+		}
+		reports = append(reports, report.sorted())
+	}
+	return reports
+}
+
+func (r Report) sorted() Report {
+	sort.Strings(r.Missing)
+	sort.Strings(r.Extra)
+	sort.Strings(r.Unused)
+	sort.Slice(r.Placeholders, func(i, j int) bool { return r.Placeholders[i].Key < r.Placeholders[j].Key })
+	return r
+}
+
+// pluralCategories are the key suffixes of plural forms
+var pluralCategories = map[string]bool{
+	PluralZero: true, PluralOne: true, PluralTwo: true,
+	PluralFew: true, PluralMany: true, PluralOther: true,
+}
+
+// placeholderPattern matches {name} parameters
+var placeholderPattern = regexp.MustCompile(`\{([A-Za-z0-9_.]+)\}`)
+
+// groupPlurals maps every message key, with plural forms folded into
+// their parent key, to the sorted set of placeholders it uses. A "count"
+// placeholder is ignored for plural forms, which often spell it out.
+func groupPlurals(bundle map[string]string) map[string][]string {
+	sets := map[string]map[string]bool{}
+	for key, message := range bundle {
+		plural := false
+		if i := strings.LastIndexByte(key, '.'); i > 0 && pluralCategories[key[i+1:]] {
+			key, plural = key[:i], true
+		}
+		if sets[key] == nil {
+			sets[key] = map[string]bool{}
+		}
+		for _, match := range placeholderPattern.FindAllStringSubmatch(message, -1) {
+			if plural && match[1] == CountParam {
+				continue
+			}
+			sets[key][match[1]] = true
+		}
+	}
+
+	out := make(map[string][]string, len(sets))
+	for key, set := range sets {
+		names := make([]string, 0, len(set))
+		for name := range set {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		out[key] = names
+	}
+	return out
+}
+
+func isReferenced(key string, referenced []string) bool {
+	for _, ref := range referenced {
+		if ref == key || (strings.HasSuffix(ref, ".") && strings.HasPrefix(key, ref)) {
+			return true
+		}
+	}
+	return false
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+var (
+	// missing counts lookups that fell back to another language or to the key
+	missing   = map[string]int64{}
+	missingMu sync.Mutex
+)
+
+// recordMissing counts a lookup of key that lang does not translate and
+// logs the first occurrence of each
+func recordMissing(lang, key string) {
+	id := lang + ":" + key
+
+	missingMu.Lock()
+	missing[id]++
+	first := missing[id] == 1
+	missingMu.Unlock()
+
+	if first {
+		utils.LogWarning(fmt.Sprintf("Missing translation for %q in %q", key, lang))
+	}
+}
+
+// MissingKeys returns how often each "lang:key" was looked up without a
+// translation since startup
+func MissingKeys() map[string]int64 {
+	missingMu.Lock()
+	defer missingMu.Unlock()
+
+	out := make(map[string]int64, len(missing))
+	for id, count := range missing {
+		out[id] = count
+	}
+	return out
+}
@@ -0,0 +1,151 @@
+package locale
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Params are the named values interpolated into a message, e.g. {minutes}
+type Params map[string]interface{}
+
+// CountParam selects the plural form of a message
+const CountParam = "count"
+
+// T returns the message for key in lang with params interpolated.
+//
+// Placeholders are written {name}; unknown placeholders are left as they
+// are. When params has a "count" and key is an object of CLDR plural forms
+// ("one", "few", "other", ...), the form for count in lang is used:
+//
+//	"OTP_EXPIRES_IN": {
+//	    "one": "OTP expires in {count} minute",
+//	    "other": "OTP expires in {count} minutes"
+//	}
+//
+// The key itself is returned when no language defines it.
+func T(lang, key string, params ...Params) string {
+	merged := Params{}
+	for _, p := range params {
+		for name, value := range p {
+			merged[name] = value
+		}
+	}
+
+	mu.RLock()
+	message, ok := lookupPlural(lang, key, merged)
+	if !ok {
+		message, ok = lookup(lang, key)
+	}
+	translated := ok && translates(lang, key)
+	mu.RUnlock()
+
+	if !translated {
+		recordMissing(lang, key)
+	}
+	if !ok {
+		return key
+	}
+	return interpolate(message, merged)
+}
+
+// Lookup returns the message for key in lang without parameters, and
+// whether any language defines it. Unlike T, a missing key is not reported.
+func Lookup(lang, key string) (string, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	return lookup(lang, key)
+}
+
+// translates reports whether lang, or a less specific tag, defines key
+// either directly or as plural forms. Callers must hold mu.
+func translates(lang, key string) bool {
+	for tag := lang; tag != ""; tag = parentTag(tag) {
+		if tag == defaultLang {
+			return true
+		}
+		if _, ok := messages[tag][key]; ok {
+			return true
+		}
+		if _, ok := messages[tag][key+"."+PluralOther]; ok {
+			return true
+		}
+	}
+	return lang == defaultLang
+}
+
+// lookupPlural finds the plural form of key for the count param. Callers must hold mu.
+func lookupPlural(lang, key string, params Params) (string, bool) {
+	count, ok := params[CountParam]
+	if !ok {
+		return "", false
+	}
+	n, ok := toFloat(count)
+	if !ok {
+		return "", false
+	}
+
+	if message, ok := lookup(lang, key+"."+PluralCategory(lang, n)); ok {
+		return message, true
+	}
+	return lookup(lang, key+".other")
+}
+
+// interpolate replaces {name} placeholders with params
+func interpolate(message string, params Params) string {
+	if len(params) == 0 || !strings.Contains(message, "{") {
+		return message
+	}
+
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(message, '{')
+		if start < 0 {
+			break
+		}
+		end := strings.IndexByte(message[start:], '}')
+		if end < 0 {
+			break
+		}
+		end += start
+
+		b.WriteString(message[:start])
+		if value, ok := params[message[start+1:end]]; ok {
+			fmt.Fprint(&b, value)
+		} else {
+			b.WriteString(message[start : end+1])
+		}
+		message = message[end+1:]
+	}
+	b.WriteString(message)
+	return b.String()
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	}
+	return 0, false
+}
@@ -0,0 +1,91 @@
+package locale
+
+import "testing"
+
+// useMessages replaces the catalogue for the duration of a test
+func useMessages(t *testing.T, catalogue map[string]map[string]string) {
+	t.Helper()
+	mu.Lock()
+	previous, previousDefault := messages, defaultLang
+	messages, defaultLang = catalogue, "en"
+	mu.Unlock()
+
+	t.Cleanup(func() {
+		mu.Lock()
+		messages, defaultLang = previous, previousDefault
+		mu.Unlock()
+	})
+}
+
+func TestT(t *testing.T) {
+	useMessages(t, map[string]map[string]string{
+		"en": {
+			"GREETING":          "Hello {name}, welcome to {app}",
+			"ONLY_EN":           "English only",
+			"OTP_EXPIRES.one":   "OTP expires in {count} minute",
+			"OTP_EXPIRES.other": "OTP expires in {count} minutes",
+		},
+		"hi": {
+			"GREETING": "नमस्ते {name}",
+		},
+		"ru": {
+			"OTP_EXPIRES.one":   "{count} минуту",
+			"OTP_EXPIRES.few":   "{count} минуты",
+			"OTP_EXPIRES.other": "{count} минут",
+		},
+	})
+
+	tests := []struct {
+		name   string
+		lang   string
+		key    string
+		params []Params
+		want   string
+	}{
+		{name: "interpolation", lang: "en", key: "GREETING", params: []Params{{"name": "Ada", "app": "Fiber"}}, want: "Hello Ada, welcome to Fiber"},
+		{name: "params are merged", lang: "en", key: "GREETING", params: []Params{{"name": "Ada"}, {"app": "Fiber"}}, want: "Hello Ada, welcome to Fiber"},
+		{name: "unknown placeholder is kept", lang: "en", key: "GREETING", params: []Params{{"name": "Ada"}}, want: "Hello Ada, welcome to {app}"},
+		{name: "no params", lang: "en", key: "GREETING", want: "Hello {name}, welcome to {app}"},
+		{name: "translated", lang: "hi", key: "GREETING", params: []Params{{"name": "Ada"}}, want: "नमस्ते Ada"},
+		{name: "region falls back to language", lang: "hi-IN", key: "GREETING", params: []Params{{"name": "Ada"}}, want: "नमस्ते Ada"},
+		{name: "falls back to the default language", lang: "hi", key: "ONLY_EN", want: "English only"},
+		{name: "unknown key", lang: "en", key: "NOPE", want: "NOPE"},
+		{name: "plural one", lang: "en", key: "OTP_EXPIRES", params: []Params{{"count": 1}}, want: "OTP expires in 1 minute"},
+		{name: "plural other", lang: "en", key: "OTP_EXPIRES", params: []Params{{"count": int64(5)}}, want: "OTP expires in 5 minutes"},
+		{name: "plural few", lang: "ru", key: "OTP_EXPIRES", params: []Params{{"count": 3}}, want: "3 минуты"},
+		{name: "missing form uses other", lang: "ru", key: "OTP_EXPIRES", params: []Params{{"count": 5}}, want: "5 минут"},
+		{name: "plural in the default language", lang: "hi", key: "OTP_EXPIRES", params: []Params{{"count": 2}}, want: "OTP expires in 2 minutes"},
+		{name: "non-numeric count", lang: "en", key: "GREETING", params: []Params{{"count": "many", "name": "Ada", "app": "Fiber"}}, want: "Hello Ada, welcome to Fiber"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := T(tt.lang, tt.key, tt.params...); got != tt.want {
+				t.Errorf("T(%q, %q) = %q, want %q", tt.lang, tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		params  Params
+		want    string
+	}{
+		{name: "repeated placeholder", message: "{n} and {n}", params: Params{"n": 1}, want: "1 and 1"},
+		{name: "unclosed brace", message: "Hello {name", params: Params{"name": "Ada"}, want: "Hello {name"},
+		{name: "empty placeholder", message: "{} {name}", params: Params{"name": "Ada"}, want: "{} Ada"},
+		{name: "adjacent placeholders", message: "{a}{b}", params: Params{"a": "x", "b": "y"}, want: "xy"},
+		{name: "float value", message: "{n}%", params: Params{"n": 2.5}, want: "2.5%"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := interpolate(tt.message, tt.params); got != tt.want {
+				t.Errorf("interpolate(%q) = %q, want %q", tt.message, got, tt.want)
+			}
+		})
+	}
+}
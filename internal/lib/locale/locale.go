@@ -3,9 +3,16 @@ package locale
 import (
 	"encoding/json"
 	"fmt"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
+
+	"github.com/addixit1/fiber-boilerplate/locales"
+	"gopkg.in/yaml.v3"
 )
 
 var (
@@ -15,71 +22,292 @@ var (
 
 	// Default language
 	defaultLang = "en"
+
+	// modules are the per-module bundles merged into the global catalogue
+	modules []source
+
+	// overrideDir is read last, replacing embedded messages key by key
+	overrideDir string
 )
 
-// Load loads all locale files from the locales directory
-func Load() error {
-	localesDir := "locales"
+// source is a set of bundles merged into the catalogue
+type source struct {
+	name string
+	fsys fs.FS
+}
+
+// Register adds a module's bundles (<lang>.json or .yaml files at the
+// root of fsys) to the catalogue. Call it before Load; modules are
+// registered by modules.RegisterLocales. A module may not redefine a key of
+// another bundle.
+func Register(name string, fsys fs.FS) {
+	mu.Lock()
+	defer mu.Unlock()
+	modules = append(modules, source{name: name, fsys: fsys})
+}
 
-	// Read all JSON files in locales directory
-	files, err := filepath.Glob(filepath.Join(localesDir, "*.json"))
+// SetOverrideDir sets a directory whose bundles are read after the
+// embedded ones and replace their messages key by key. Empty disables it.
+func SetOverrideDir(dir string) {
+	mu.Lock()
+	defer mu.Unlock()
+	overrideDir = dir
+}
+
+// OverrideDir returns the override directory, or "" when none is set
+func OverrideDir() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return overrideDir
+}
+
+// Load builds the catalogue from the embedded bundles, the registered
+// module bundles and the override directory. Like Reload, it fails when
+// the default language has no bundle.
+func Load() error {
+	loaded, err := readCatalogue()
 	if err != nil {
-		return fmt.Errorf("failed to read locales directory: %w", err)
+		return err
 	}
 
-	if len(files) == 0 {
-		return fmt.Errorf("no locale files found in %s", localesDir)
+	mu.Lock()
+	defer mu.Unlock()
+
+	if _, ok := loaded[defaultLang]; !ok {
+		return fmt.Errorf("default language %q is missing", defaultLang)
+	}
+
+	messages = loaded
+	return nil
+}
+
+// Reload rebuilds the catalogue, validates it and swaps it in atomically.
+// The current messages stay in place when anything is invalid.
+// It returns a human readable summary of what changed.
+func Reload() ([]string, error) {
+	loaded, err := readCatalogue()
+	if err != nil {
+		return nil, err
 	}
 
 	mu.Lock()
 	defer mu.Unlock()
 
+	if _, ok := loaded[defaultLang]; !ok {
+		return nil, fmt.Errorf("default language %q is missing", defaultLang)
+	}
+
+	changes := diffMessages(messages, loaded)
+	messages = loaded
+	return changes, nil
+}
+
+// Files lists the bundles in the override directory, which are the only
+// ones that can change while the server runs
+func Files() []string {
+	dir := OverrideDir()
+	if dir == "" {
+		return nil
+	}
+
+	var files []string
+	for _, pattern := range []string{"*.json", "*.yaml", "*.yml"} {
+		matches, _ := filepath.Glob(filepath.Join(dir, pattern))
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// readCatalogue merges the embedded, module and override bundles
+func readCatalogue() (map[string]map[string]string, error) {
+	mu.RLock()
+	sources := append([]source{{name: "global", fsys: locales.FS}}, modules...)
+	dir := overrideDir
+	mu.RUnlock()
+
+	catalogue := map[string]map[string]string{}
+	owners := map[string]string{}
+	for _, src := range sources {
+		bundles, err := readAll(src.fsys, src.name)
+		if err != nil {
+			return nil, err
+		}
+		for lang, bundle := range bundles {
+			if catalogue[lang] == nil {
+				catalogue[lang] = map[string]string{}
+			}
+			for key, message := range bundle {
+				if owner, exists := owners[lang+":"+key]; exists {
+					return nil, fmt.Errorf("locale key %q (%s) is defined by both %s and %s", key, lang, owner, src.name)
+				}
+				owners[lang+":"+key] = src.name
+				catalogue[lang][key] = message
+			}
+		}
+	}
+
+	if dir != "" {
+		bundles, err := readAll(os.DirFS(dir), dir)
+		if err != nil {
+			return nil, err
+		}
+		for lang, bundle := range bundles {
+			if catalogue[lang] == nil {
+				catalogue[lang] = map[string]string{}
+			}
+			for key, message := range bundle {
+				catalogue[lang][key] = message
+			}
+		}
+	}
+
+	if len(catalogue) == 0 {
+		return nil, fmt.Errorf("no locale files found")
+	}
+	return catalogue, nil
+}
+
+// readAll parses every locale file at the root of fsys into a fresh message
+// map. Nested objects are flattened into dotted keys ("validation.required").
+// name identifies fsys in errors.
+func readAll(fsys fs.FS, name string) (map[string]map[string]string, error) {
+	var files []string
+	for _, pattern := range []string{"*.json", "*.yaml", "*.yml"} {
+		matches, err := fs.Glob(fsys, pattern)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read locales from %s: %w", name, err)
+		}
+		files = append(files, matches...)
+	}
+
+	loaded := make(map[string]map[string]string, len(files))
 	for _, file := range files {
 		// Extract language code from filename (e.g., "en" from "en.json")
-		lang := filepath.Base(file)
-		lang = lang[:len(lang)-5] // Remove .json extension
+		lang := strings.TrimSuffix(file, path.Ext(file))
+		if _, exists := loaded[lang]; exists {
+			return nil, fmt.Errorf("locale %q is defined by more than one file in %s", lang, name)
+		}
 
 		// Read file
-		data, err := os.ReadFile(file)
+		data, err := fs.ReadFile(fsys, file)
 		if err != nil {
-			return fmt.Errorf("failed to read locale file %s: %w", file, err)
+			return nil, fmt.Errorf("failed to read locale file %s/%s: %w", name, file, err)
 		}
 
-		// Parse JSON
-		var localeMessages map[string]string
-		if err := json.Unmarshal(data, &localeMessages); err != nil {
-			return fmt.Errorf("failed to parse locale file %s: %w", file, err)
+		// Parse JSON or YAML
+		var tree map[string]interface{}
+		if path.Ext(file) == ".json" {
+			err = json.Unmarshal(data, &tree)
+		} else {
+			err = yaml.Unmarshal(data, &tree)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse locale file %s/%s: %w", name, file, err)
 		}
 
-		messages[lang] = localeMessages
+		localeMessages := map[string]string{}
+		if err := flatten(tree, "", localeMessages); err != nil {
+			return nil, fmt.Errorf("invalid locale file %s/%s: %w", name, file, err)
+		}
+		loaded[lang] = localeMessages
 	}
 
+	return loaded, nil
+}
+
+// flatten copies nested messages into out under dotted keys
+func flatten(tree map[string]interface{}, prefix string, out map[string]string) error {
+	for key, value := range tree {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch typed := value.(type) {
+		case string:
+			out[key] = typed
+		case map[string]interface{}:
+			if err := flatten(typed, key, out); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%s: expected a string or an object, got %T", key, value)
+		}
+	}
 	return nil
 }
 
-// Get retrieves a message for a given key and language
-func Get(lang, key string) string {
-	mu.RLock()
-	defer mu.RUnlock()
+// diffMessages summarises added/removed languages and changed keys per language
+func diffMessages(old, loaded map[string]map[string]string) []string {
+	var changes []string
 
-	// Try to get message for requested language
-	if langMessages, ok := messages[lang]; ok {
-		if message, ok := langMessages[key]; ok {
-			return message
+	for lang, next := range loaded {
+		prev, ok := old[lang]
+		if !ok {
+			changes = append(changes, fmt.Sprintf("%s: added (%d keys)", lang, len(next)))
+			continue
 		}
-	}
 
-	// Fallback to default language
-	if lang != defaultLang {
-		if langMessages, ok := messages[defaultLang]; ok {
-			if message, ok := langMessages[key]; ok {
-				return message
+		added, removed, updated := 0, 0, 0
+		for key, value := range next {
+			before, ok := prev[key]
+			switch {
+			case !ok:
+				added++
+			case before != value:
+				updated++
 			}
 		}
+		for key := range prev {
+			if _, ok := next[key]; !ok {
+				removed++
+			}
+		}
+		if added+removed+updated > 0 {
+			changes = append(changes, fmt.Sprintf("%s: %d added, %d removed, %d updated", lang, added, removed, updated))
+		}
+	}
+
+	for lang := range old {
+		if _, ok := loaded[lang]; !ok {
+			changes = append(changes, lang+": removed")
+		}
 	}
 
-	// If still not found, return the key itself
-	return key
+	sort.Strings(changes)
+	return changes
+}
+
+// Get retrieves a message for a given key and language, falling back
+// through less specific tags (pt-BR → pt) and then the default language.
+// Use T for messages with parameters or plural forms.
+func Get(lang, key string) string {
+	return T(lang, key)
+}
+
+// lookup finds key for lang, falling back through less specific tags
+// and then the default language. Callers must hold mu.
+func lookup(lang, key string) (string, bool) {
+	for tag := lang; tag != ""; tag = parentTag(tag) {
+		if message, ok := messages[tag][key]; ok {
+			return message, true
+		}
+	}
+	message, ok := messages[defaultLang][key]
+	return message, ok
+}
+
+// parentTag strips the last subtag of a language tag, e.g. pt-BR → pt,
+// along with a single-letter extension marker left behind (RFC 4647 lookup)
+func parentTag(tag string) string {
+	i := strings.LastIndexAny(tag, "-_")
+	if i <= 0 {
+		return ""
+	}
+	tag = tag[:i]
+	if j := strings.LastIndexAny(tag, "-_"); j > 0 && len(tag)-j == 2 {
+		tag = tag[:j]
+	}
+	return tag
 }
 
 // SetDefaultLanguage sets the default fallback language
@@ -0,0 +1,92 @@
+package locale
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// languageRange is one entry of an Accept-Language header
+type languageRange struct {
+	tag string
+	q   float64
+}
+
+// Negotiate picks the best available language for an Accept-Language
+// header using RFC 4647 lookup: ranges are tried in order of quality, each
+// falling back through its less specific tags (pt-BR → pt). The default
+// language is returned when nothing matches.
+func Negotiate(header string) string {
+	for _, r := range parseAcceptLanguage(header) {
+		if r.tag == "*" {
+			break
+		}
+		if lang, ok := Match(r.tag); ok {
+			return lang
+		}
+	}
+	return DefaultLanguage()
+}
+
+// Match returns the available language for tag, falling back through its
+// less specific tags. Tags are compared case-insensitively and "_" is
+// accepted in place of "-".
+func Match(tag string) (string, bool) {
+	tag = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(tag), "_", "-"))
+	if tag == "" {
+		return "", false
+	}
+
+	mu.RLock()
+	defer mu.RUnlock()
+
+	for ; tag != ""; tag = parentTag(tag) {
+		for lang := range messages {
+			if strings.EqualFold(lang, tag) {
+				return lang, true
+			}
+		}
+	}
+	return "", false
+}
+
+// DefaultLanguage returns the fallback language
+func DefaultLanguage() string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return defaultLang
+}
+
+// parseAcceptLanguage splits a header such as "fr-CA,en;q=0.8" into ranges
+// ordered by descending quality, keeping header order for ties. Ranges
+// with q=0 or an invalid q are dropped.
+func parseAcceptLanguage(header string) []languageRange {
+	var ranges []languageRange
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || strings.TrimSpace(name) != "q" {
+				continue
+			}
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil || parsed < 0 || parsed > 1 {
+				q = 0
+			} else {
+				q = parsed
+			}
+		}
+		if q > 0 {
+			ranges = append(ranges, languageRange{tag: tag, q: q})
+		}
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool { return ranges[i].q > ranges[j].q })
+	return ranges
+}
@@ -0,0 +1,81 @@
+package locale
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNegotiate(t *testing.T) {
+	useMessages(t, map[string]map[string]string{
+		"en":    {},
+		"hi":    {},
+		"pt":    {},
+		"pt-BR": {},
+	})
+
+	tests := []struct {
+		header string
+		want   string
+	}{
+		{header: "", want: "en"},
+		{header: "hi", want: "hi"},
+		{header: "HI-in", want: "hi"},
+		{header: "pt-BR", want: "pt-BR"},
+		{header: "pt_br", want: "pt-BR"},
+		{header: "pt-PT", want: "pt"},
+		{header: "fr-CA,hi;q=0.8,en;q=0.9", want: "en"},
+		{header: "en;q=0.5,hi", want: "hi"},
+		{header: "hi;q=0,pt", want: "pt"},
+		{header: "fr,de", want: "en"},
+		{header: "fr,*,hi", want: "en"},
+		{header: "hi;q=abc,pt;q=0.1", want: "pt"},
+		{header: "zh-Hant-x-private", want: "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := Negotiate(tt.header); got != tt.want {
+				t.Errorf("Negotiate(%q) = %q, want %q", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   []languageRange
+	}{
+		{header: "", want: nil},
+		{header: "fr-CA, fr;q=0.9, en;q=0.8", want: []languageRange{{"fr-CA", 1}, {"fr", 0.9}, {"en", 0.8}}},
+		{header: "en;q=0.5,hi,pt;q=0.5", want: []languageRange{{"hi", 1}, {"en", 0.5}, {"pt", 0.5}}},
+		{header: "en;q=0,hi;q=2,pt;q=x,de", want: []languageRange{{"de", 1}}},
+		{header: " , hi ; level=1 ; q=0.3", want: []languageRange{{"hi", 0.3}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			if got := parseAcceptLanguage(tt.header); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseAcceptLanguage(%q) = %v, want %v", tt.header, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParentTag(t *testing.T) {
+	tests := []struct {
+		tag, want string
+	}{
+		{tag: "pt-BR", want: "pt"},
+		{tag: "zh-Hant-TW", want: "zh-Hant"},
+		{tag: "en-a-ext", want: "en"},
+		{tag: "en", want: ""},
+		{tag: "pt_BR", want: "pt"},
+	}
+
+	for _, tt := range tests {
+		if got := parentTag(tt.tag); got != tt.want {
+			t.Errorf("parentTag(%q) = %q, want %q", tt.tag, got, tt.want)
+		}
+	}
+}
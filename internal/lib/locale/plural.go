@@ -0,0 +1,116 @@
+package locale
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// CLDR plural categories
+const (
+	PluralZero  = "zero"
+	PluralOne   = "one"
+	PluralTwo   = "two"
+	PluralFew   = "few"
+	PluralMany  = "many"
+	PluralOther = "other"
+)
+
+// PluralCategory returns the CLDR cardinal plural category of n in lang.
+// Rules cover the common language families; unknown languages use the
+// English rule.
+func PluralCategory(lang string, n float64) string {
+	n = math.Abs(n)
+	i := int64(n)
+	v := fractionDigits(n)
+
+	base := strings.ToLower(lang)
+	if j := strings.IndexAny(base, "-_"); j > 0 {
+		if base == "pt-pt" || base == "pt_pt" {
+			base = "pt-pt"
+		} else {
+			base = base[:j]
+		}
+	}
+
+	switch base {
+	case "ja", "zh", "ko", "th", "vi", "id", "ms", "lo", "my", "km":
+		return PluralOther
+
+	case "fr", "pt":
+		if i == 0 || i == 1 {
+			return PluralOne
+		}
+
+	case "hi", "bn", "gu", "kn", "mr", "fa", "zu", "am":
+		if i == 0 || n == 1 {
+			return PluralOne
+		}
+
+	case "ru", "uk", "be":
+		if v != 0 {
+			return PluralOther
+		}
+		switch {
+		case i%10 == 1 && i%100 != 11:
+			return PluralOne
+		case i%10 >= 2 && i%10 <= 4 && (i%100 < 12 || i%100 > 14):
+			return PluralFew
+		default:
+			return PluralMany
+		}
+
+	case "pl":
+		if v != 0 {
+			return PluralOther
+		}
+		switch {
+		case i == 1:
+			return PluralOne
+		case i%10 >= 2 && i%10 <= 4 && (i%100 < 12 || i%100 > 14):
+			return PluralFew
+		default:
+			return PluralMany
+		}
+
+	case "cs", "sk":
+		switch {
+		case v != 0:
+			return PluralMany
+		case i == 1:
+			return PluralOne
+		case i >= 2 && i <= 4:
+			return PluralFew
+		}
+
+	case "ar":
+		mod := math.Mod(n, 100)
+		switch {
+		case n == 0:
+			return PluralZero
+		case n == 1:
+			return PluralOne
+		case n == 2:
+			return PluralTwo
+		case mod >= 3 && mod <= 10 && v == 0:
+			return PluralFew
+		case mod >= 11 && mod <= 99 && v == 0:
+			return PluralMany
+		}
+
+	default:
+		if i == 1 && v == 0 {
+			return PluralOne
+		}
+	}
+	return PluralOther
+}
+
+// fractionDigits returns the number of visible fraction digits of n (CLDR operand v)
+func fractionDigits(n float64) int {
+	s := strconv.FormatFloat(n, 'f', -1, 64)
+	if dot := strings.IndexByte(s, '.'); dot >= 0 {
+		return len(s) - dot - 1
+	}
+	return 0
+}
@@ -0,0 +1,66 @@
+package locale
+
+import "testing"
+
+func TestPluralCategory(t *testing.T) {
+	tests := []struct {
+		lang string
+		n    float64
+		want string
+	}{
+		{lang: "en", n: 0, want: PluralOther},
+		{lang: "en", n: 1, want: PluralOne},
+		{lang: "en", n: 1.5, want: PluralOther},
+		{lang: "en", n: 2, want: PluralOther},
+		{lang: "en-GB", n: 1, want: PluralOne},
+		{lang: "en", n: -1, want: PluralOne},
+		{lang: "xx", n: 1, want: PluralOne},
+
+		{lang: "fr", n: 0, want: PluralOne},
+		{lang: "fr", n: 1.5, want: PluralOne},
+		{lang: "fr", n: 2, want: PluralOther},
+		{lang: "pt-BR", n: 0, want: PluralOne},
+		{lang: "pt-PT", n: 0, want: PluralOther},
+		{lang: "pt_PT", n: 1, want: PluralOne},
+
+		{lang: "hi", n: 0, want: PluralOne},
+		{lang: "hi", n: 0.5, want: PluralOne},
+		{lang: "hi", n: 1, want: PluralOne},
+		{lang: "hi", n: 1.5, want: PluralOther},
+		{lang: "hi", n: 2, want: PluralOther},
+
+		{lang: "ja", n: 1, want: PluralOther},
+
+		{lang: "ru", n: 1, want: PluralOne},
+		{lang: "ru", n: 21, want: PluralOne},
+		{lang: "ru", n: 11, want: PluralMany},
+		{lang: "ru", n: 3, want: PluralFew},
+		{lang: "ru", n: 13, want: PluralMany},
+		{lang: "ru", n: 24, want: PluralFew},
+		{lang: "ru", n: 5, want: PluralMany},
+		{lang: "ru", n: 1.5, want: PluralOther},
+
+		{lang: "pl", n: 1, want: PluralOne},
+		{lang: "pl", n: 21, want: PluralMany},
+		{lang: "pl", n: 22, want: PluralFew},
+		{lang: "pl", n: 12, want: PluralMany},
+
+		{lang: "cs", n: 1, want: PluralOne},
+		{lang: "cs", n: 3, want: PluralFew},
+		{lang: "cs", n: 5, want: PluralOther},
+		{lang: "cs", n: 1.5, want: PluralMany},
+
+		{lang: "ar", n: 0, want: PluralZero},
+		{lang: "ar", n: 1, want: PluralOne},
+		{lang: "ar", n: 2, want: PluralTwo},
+		{lang: "ar", n: 103, want: PluralFew},
+		{lang: "ar", n: 111, want: PluralMany},
+		{lang: "ar", n: 100, want: PluralOther},
+	}
+
+	for _, tt := range tests {
+		if got := PluralCategory(tt.lang, tt.n); got != tt.want {
+			t.Errorf("PluralCategory(%q, %v) = %q, want %q", tt.lang, tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often expired in-memory entries are dropped
+const sweepInterval = time.Minute
+
+// memoryEntry holds the state of one key for either algorithm
+type memoryEntry struct {
+	hits    []time.Time // sliding window
+	tokens  float64     // token bucket
+	updated time.Time
+	expires time.Time
+}
+
+// memoryStore enforces limits per instance while Redis is unavailable
+type memoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]*memoryEntry
+	lastSweep time.Time
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{entries: map[string]*memoryEntry{}}
+}
+
+func (s *memoryStore) allow(key string, limit Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	entry, ok := s.entries[key]
+	if !ok {
+		entry = &memoryEntry{tokens: float64(limit.Max), updated: now}
+		s.entries[key] = entry
+	}
+	entry.expires = now.Add(limit.Window)
+
+	if limit.Algorithm == TokenBucket {
+		return entry.takeToken(limit, now), nil
+	}
+	return entry.hit(limit, now), nil
+}
+
+// hit applies the sliding window algorithm
+func (e *memoryEntry) hit(limit Limit, now time.Time) Result {
+	cutoff := now.Add(-limit.Window)
+	kept := e.hits[:0]
+	for _, t := range e.hits {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	e.hits = kept
+
+	result := Result{Limit: limit.Max}
+	if len(e.hits) < limit.Max {
+		e.hits = append(e.hits, now)
+		result.Allowed = true
+	}
+	result.Remaining = limit.Max - len(e.hits)
+	if len(e.hits) > 0 {
+		result.Reset = e.hits[0].Add(limit.Window).Sub(now)
+	}
+	if !result.Allowed {
+		result.RetryAfter = result.Reset
+	}
+	return result
+}
+
+// takeToken applies the token bucket algorithm
+func (e *memoryEntry) takeToken(limit Limit, now time.Time) Result {
+	rate := float64(limit.Max) / float64(limit.Window)
+	if now.After(e.updated) {
+		e.tokens = math.Min(float64(limit.Max), e.tokens+float64(now.Sub(e.updated))*rate)
+	}
+	e.updated = now
+
+	result := Result{Limit: limit.Max}
+	if e.tokens >= 1 {
+		e.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration(math.Ceil((1 - e.tokens) / rate))
+	}
+	result.Remaining = int(e.tokens)
+	result.Reset = time.Duration(math.Ceil((float64(limit.Max) - e.tokens) / rate))
+	return result
+}
+
+// sweep drops entries whose window has passed
+func (s *memoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+	for key, entry := range s.entries {
+		if now.After(entry.expires) {
+			delete(s.entries, key)
+		}
+	}
+}
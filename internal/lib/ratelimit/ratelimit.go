@@ -0,0 +1,87 @@
+package ratelimit
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/addixit1/fiber-boilerplate/internal/lib/redis"
+	"github.com/addixit1/fiber-boilerplate/internal/utils"
+	"github.com/addixit1/fiber-boilerplate/internal/utils/errortracker"
+)
+
+// Algorithm selects how requests are counted
+type Algorithm string
+
+const (
+	// SlidingWindow allows Max requests in any Window-long interval
+	SlidingWindow Algorithm = "sliding_window"
+
+	// TokenBucket refills Max tokens evenly over Window and allows bursts up to Max
+	TokenBucket Algorithm = "token_bucket"
+)
+
+// Limit is the allowance for a single key
+type Limit struct {
+	Max       int
+	Window    time.Duration
+	Algorithm Algorithm
+}
+
+// Result is the outcome of a rate limit check
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+
+	// Reset is how long until the full allowance is available again
+	Reset time.Duration
+
+	// RetryAfter is how long a rejected client should wait; zero when allowed
+	RetryAfter time.Duration
+}
+
+// store applies a limit to a key at the given time
+type store interface {
+	allow(key string, limit Limit, now time.Time) (Result, error)
+}
+
+// retryInterval is how long Redis is skipped after a failure, so requests
+// are not slowed down by connection timeouts while it is down
+const retryInterval = 5 * time.Second
+
+var (
+	shared   store = redisStore{}
+	fallback       = newMemoryStore()
+
+	// degraded is set while Redis is failing and limits are kept in memory
+	degraded atomic.Bool
+
+	// retryAt is when Redis is tried again, in unix nanoseconds
+	retryAt atomic.Int64
+)
+
+// Allow counts a request for key against limit. Limits are shared through
+// Redis; while Redis is unavailable each instance enforces them in memory.
+func Allow(key string, limit Limit) Result {
+	if limit.Algorithm == "" {
+		limit.Algorithm = SlidingWindow
+	}
+	now := time.Now()
+
+	if redis.Client != nil && now.UnixNano() >= retryAt.Load() {
+		result, err := shared.allow(key, limit, now)
+		if err == nil {
+			if degraded.CompareAndSwap(true, false) {
+				utils.LogSuccess("Rate limiter recovered, using Redis again")
+			}
+			return result
+		}
+		retryAt.Store(now.Add(retryInterval).UnixNano())
+		if degraded.CompareAndSwap(false, true) {
+			errortracker.Track(errortracker.LayerMiddleware, "Rate limiter falling back to in-memory limits", err)
+		}
+	}
+
+	result, _ := fallback.allow(key, limit, now)
+	return result
+}
@@ -0,0 +1,105 @@
+package ratelimit
+
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/addixit1/fiber-boilerplate/internal/lib/redis"
+	goredis "github.com/redis/go-redis/v9"
+)
+
+// slidingWindow keeps one sorted-set member per request, scored by its time in ms.
+// Returns {allowed, remaining, reset ms, retry after ms}.
+var slidingWindow = goredis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local max = tonumber(ARGV[3])
+
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", now - window)
+local count = redis.call("ZCARD", KEYS[1])
+
+local allowed = 0
+if count < max then
+	redis.call("ZADD", KEYS[1], now, ARGV[4])
+	count = count + 1
+	allowed = 1
+end
+redis.call("PEXPIRE", KEYS[1], window)
+
+local oldest = redis.call("ZRANGE", KEYS[1], 0, 0, "WITHSCORES")
+local reset = 0
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+local retry = 0
+if allowed == 0 then
+	retry = reset
+end
+return {allowed, max - count, reset, retry}
+`)
+
+// tokenBucket stores the token count and the time it was last refilled.
+// Returns {allowed, remaining, reset ms, retry after ms}.
+var tokenBucket = goredis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local max = tonumber(ARGV[3])
+local rate = max / window
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil or ts == nil then
+	tokens = max
+	ts = now
+end
+if now > ts then
+	tokens = math.min(max, tokens + (now - ts) * rate)
+end
+
+local allowed = 0
+local retry = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	retry = math.ceil((1 - tokens) / rate)
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", now)
+redis.call("PEXPIRE", KEYS[1], window)
+
+return {allowed, math.floor(tokens), math.ceil((max - tokens) / rate), retry}
+`)
+
+// sequence makes sliding window members unique within a millisecond
+var sequence atomic.Uint64
+
+// redisStore shares limits between instances through Redis
+type redisStore struct{}
+
+func (redisStore) allow(key string, limit Limit, now time.Time) (Result, error) {
+	script := slidingWindow
+	if limit.Algorithm == TokenBucket {
+		script = tokenBucket
+	}
+
+	args := []interface{}{now.UnixMilli(), limit.Window.Milliseconds(), limit.Max}
+	if limit.Algorithm != TokenBucket {
+		args = append(args, strconv.FormatInt(now.UnixNano(), 36)+"-"+strconv.FormatUint(sequence.Add(1), 36))
+	}
+
+	values, err := script.Run(redis.Ctx, redis.Client, []string{"ratelimit:" + key}, args...).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+
+	return Result{
+		Allowed:    values[0] == 1,
+		Limit:      limit.Max,
+		Remaining:  int(values[1]),
+		Reset:      time.Duration(values[2]) * time.Millisecond,
+		RetryAfter: time.Duration(values[3]) * time.Millisecond,
+	}, nil
+}
@@ -15,22 +15,25 @@ var (
 	Client *redis.Client
 )
 
+// Nil is returned when a key does not exist
+const Nil = redis.Nil
+
 func Init() {
 	Client = redis.NewClient(&redis.Options{
-		Addr:         config.Config.RedisURI,
-		Password:     "", // set if required
-		DB:           0,
-		DialTimeout:  5 * time.Second,
-		ReadTimeout:  3 * time.Second,
-		WriteTimeout: 3 * time.Second,
-		PoolSize:     20,
-		MinIdleConns: 5,
+		Addr:         config.Config.Redis.Addr,
+		Password:     config.Config.Redis.Password,
+		DB:           config.Config.Redis.DB,
+		DialTimeout:  config.Config.Redis.DialTimeout,
+		ReadTimeout:  config.Config.Redis.ReadTimeout,
+		WriteTimeout: config.Config.Redis.WriteTimeout,
+		PoolSize:     config.Config.Redis.PoolSize,
+		MinIdleConns: config.Config.Redis.MinIdleConns,
 	})
 
 	if err := Client.Ping(Ctx).Err(); err != nil {
 		log.Fatalf("redis connection failed: %v", err)
 	}
-	utils.LogDatabase("Redis connected successfully on localhost:6379")
+	utils.LogDatabase("Redis connected successfully on " + config.Config.Redis.Addr)
 }
 
 func Publish(channel string, message string) error {
@@ -55,3 +58,30 @@ func Set(key string, value string, ttl time.Duration) error {
 func Get(key string) (string, error) {
 	return Client.Get(Ctx, key).Result()
 }
+
+// incrWithTTL increments a counter and starts its expiry on creation, atomically
+var incrWithTTL = redis.NewScript(`
+local n = redis.call("INCR", KEYS[1])
+if n == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return n
+`)
+
+// IncrWithTTL increments key and sets ttl when the key is created
+func IncrWithTTL(key string, ttl time.Duration) (int64, error) {
+	return incrWithTTL.Run(Ctx, Client, []string{key}, ttl.Milliseconds()).Int64()
+}
+
+// TTL returns the remaining time to live of key, or 0 when it does not exist
+func TTL(key string) (time.Duration, error) {
+	ttl, err := Client.TTL(Ctx, key).Result()
+	if err != nil || ttl < 0 {
+		return 0, err
+	}
+	return ttl, nil
+}
+
+func Del(keys ...string) error {
+	return Client.Del(Ctx, keys...).Err()
+}
@@ -0,0 +1,155 @@
+package reqctx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Platform is the client platform sent in the platform header
+type Platform int
+
+const (
+	PlatformAndroid Platform = 1
+	PlatformIOS     Platform = 2
+	PlatformWeb     Platform = 3
+)
+
+// String returns the platform name
+func (p Platform) String() string {
+	switch p {
+	case PlatformAndroid:
+		return "android"
+	case PlatformIOS:
+		return "ios"
+	case PlatformWeb:
+		return "web"
+	}
+	return "unknown"
+}
+
+// Valid reports whether p is a known platform
+func (p Platform) Valid() bool {
+	return p >= PlatformAndroid && p <= PlatformWeb
+}
+
+// PrincipalKind is how a principal authenticated
+type PrincipalKind string
+
+const (
+	PrincipalBasic  PrincipalKind = "basic"
+	PrincipalAPIKey PrincipalKind = "api_key"
+	PrincipalBearer PrincipalKind = "bearer"
+)
+
+// Principal is the authenticated caller
+type Principal struct {
+	Kind PrincipalKind
+
+	// ID is the username, API key ID or token subject
+	ID string
+
+	// Name is a display name, e.g. the API key name
+	Name   string
+	Scopes []string
+}
+
+// RequestContext holds the per-request values parsed from headers and
+// set by the auth middlewares
+type RequestContext struct {
+	RequestID string
+	Lang      string
+	Platform  Platform
+
+	// Location is the caller's timezone; UTC when none was sent
+	Location *time.Location
+
+	// Offset is the caller's UTC offset at the time of the request
+	Offset time.Duration
+
+	AppVersion   string
+	RouteVersion string
+
+	// Principal is nil until an auth middleware accepts the request
+	Principal *Principal
+}
+
+// Defaults for requests that did not send a header
+const (
+	DefaultLang         = "en"
+	DefaultPlatform     = PlatformWeb
+	DefaultAppVersion   = "v1"
+	DefaultRouteVersion = "v1"
+)
+
+// localsKey stores the RequestContext in c.Locals
+const localsKey = "reqctx"
+
+// contextKey stores the RequestContext in c.UserContext()
+type contextKey struct{}
+
+// New returns a RequestContext with defaults for every value
+func New() *RequestContext {
+	return &RequestContext{
+		RequestID:    NewRequestID(),
+		Lang:         DefaultLang,
+		Platform:     DefaultPlatform,
+		Location:     time.UTC,
+		AppVersion:   DefaultAppVersion,
+		RouteVersion: DefaultRouteVersion,
+	}
+}
+
+// Set stores rc for the request, both in c.Locals and in c.UserContext()
+// so services receiving a context.Context can read it with FromContext
+func Set(c *fiber.Ctx, rc *RequestContext) {
+	c.Locals(localsKey, rc)
+	c.SetUserContext(context.WithValue(c.UserContext(), contextKey{}, rc))
+}
+
+// From returns the request's RequestContext. Requests that did not pass
+// through the middleware get one with defaults, so it is never nil.
+func From(c *fiber.Ctx) *RequestContext {
+	if rc, ok := c.Locals(localsKey).(*RequestContext); ok {
+		return rc
+	}
+	rc := New()
+	Set(c, rc)
+	return rc
+}
+
+// FromContext returns the RequestContext stored in ctx by Set
+func FromContext(ctx context.Context) (*RequestContext, bool) {
+	rc, ok := ctx.Value(contextKey{}).(*RequestContext)
+	return rc, ok
+}
+
+// Lang returns the request language
+func Lang(c *fiber.Ctx) string {
+	return From(c).Lang
+}
+
+// Location returns the caller's timezone
+func Location(c *fiber.Ctx) *time.Location {
+	return From(c).Location
+}
+
+// PrincipalOf returns the authenticated caller, or nil
+func PrincipalOf(c *fiber.Ctx) *Principal {
+	return From(c).Principal
+}
+
+// SetPrincipal records the authenticated caller
+func SetPrincipal(c *fiber.Ctx, p *Principal) {
+	From(c).Principal = p
+}
+
+// NewRequestID returns a random 128-bit hex ID
+func NewRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
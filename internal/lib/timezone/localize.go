@@ -0,0 +1,147 @@
+package timezone
+
+import (
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/addixit1/fiber-boilerplate/internal/lib/reqctx"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	dateTimeType = reflect.TypeOf(primitive.DateTime(0))
+)
+
+// Localize returns a copy of v with every time.Time in exported struct
+// fields, slices, arrays, maps and pointers moved to loc, so it marshals
+// with the caller's offset. Zero times and strings are left alone, and v
+// itself is not modified. primitive.DateTime values held in interfaces
+// (e.g. in a bson.M) become time.Time.
+func Localize(v interface{}, loc *time.Location) interface{} {
+	if v == nil || loc == nil || loc == time.UTC {
+		return v
+	}
+	return localize(reflect.ValueOf(v), loc).Interface()
+}
+
+// JSON sends body with its timestamps in the caller's timezone, e.g.
+// timezone.JSON(c.Status(200), config.List(users, lang)). Responses carrying
+// data go through it, so no controller localizes on its own.
+func JSON(c *fiber.Ctx, body interface{}) error {
+	return c.JSON(Localize(body, reqctx.Location(c)))
+}
+
+func localize(v reflect.Value, loc *time.Location) reflect.Value {
+	if v.Type() == timeType {
+		if t := v.Interface().(time.Time); !t.IsZero() {
+			return reflect.ValueOf(t.In(loc))
+		}
+		return v
+	}
+	if !mayHoldTime(v.Type()) {
+		return v
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type().Elem())
+		out.Elem().Set(localize(v.Elem(), loc))
+		return out
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		inner := v.Elem()
+		if inner.Type() == dateTimeType {
+			inner = reflect.ValueOf(inner.Interface().(primitive.DateTime).Time())
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(localize(inner, loc))
+		return out
+
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		for i := 0; i < out.NumField(); i++ {
+			if field := out.Field(i); field.CanSet() {
+				field.Set(localize(v.Field(i), loc))
+			}
+		}
+		return out
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(localize(v.Index(i), loc))
+		}
+		return out
+
+	case reflect.Array:
+		out := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(localize(v.Index(i), loc))
+		}
+		return out
+
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(iter.Key(), localize(iter.Value(), loc))
+		}
+		return out
+	}
+	return v
+}
+
+// holdsTime caches mayHoldTime by type
+var holdsTime sync.Map
+
+// mayHoldTime reports whether values of t can contain a time.Time, so
+// Localize skips copying IDs, strings and other plain data
+func mayHoldTime(t reflect.Type) bool {
+	if cached, ok := holdsTime.Load(t); ok {
+		return cached.(bool)
+	}
+	holds := holdsTimeIn(t, map[reflect.Type]bool{})
+	holdsTime.Store(t, holds)
+	return holds
+}
+
+// holdsTimeIn is mayHoldTime, ending recursive types at the types in visiting
+func holdsTimeIn(t reflect.Type, visiting map[reflect.Type]bool) bool {
+	if visiting[t] {
+		return false
+	}
+	visiting[t] = true
+
+	switch t.Kind() {
+	case reflect.Interface:
+		return true
+	case reflect.Struct:
+		if t == timeType {
+			return true
+		}
+		for i := 0; i < t.NumField(); i++ {
+			if field := t.Field(i); field.IsExported() && holdsTimeIn(field.Type, visiting) {
+				return true
+			}
+		}
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return holdsTimeIn(t.Elem(), visiting)
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+package timezone
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type localizeItem struct {
+	Name      string
+	CreatedAt time.Time
+	DeletedAt *time.Time
+	Children  []localizeItem
+	Meta      map[string]interface{}
+	hidden    time.Time
+}
+
+func TestLocalize(t *testing.T) {
+	loc := time.FixedZone("UTC+05:30", 19800)
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	local := at.In(loc)
+
+	tests := []struct {
+		name string
+		in   interface{}
+		want interface{}
+	}{
+		{name: "nil", in: nil, want: nil},
+		{name: "time", in: at, want: local},
+		{name: "zero time is kept", in: time.Time{}, want: time.Time{}},
+		{name: "string is kept", in: "2024-05-01T12:00:00Z", want: "2024-05-01T12:00:00Z"},
+		{
+			name: "struct fields, pointers and slices",
+			in: localizeItem{
+				Name:      "a",
+				CreatedAt: at,
+				DeletedAt: &at,
+				Children:  []localizeItem{{Name: "b", CreatedAt: at}},
+				hidden:    at,
+			},
+			want: localizeItem{
+				Name:      "a",
+				CreatedAt: local,
+				DeletedAt: &local,
+				Children:  []localizeItem{{Name: "b", CreatedAt: local}},
+				hidden:    at,
+			},
+		},
+		{
+			name: "DateTime in a bson.M becomes a time",
+			in:   bson.M{"created_at": primitive.NewDateTimeFromTime(at), "n": 1},
+			want: bson.M{"created_at": local, "n": 1},
+		},
+		{
+			name: "nested maps and arrays",
+			in:   &localizeItem{Meta: map[string]interface{}{"seen": bson.A{at}}},
+			want: &localizeItem{Meta: map[string]interface{}{"seen": bson.A{local}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Localize(tt.in, loc)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Localize() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocalizeKeepsInput(t *testing.T) {
+	loc := time.FixedZone("UTC+05:30", 19800)
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	item := &localizeItem{CreatedAt: at, DeletedAt: &at, Children: []localizeItem{{CreatedAt: at}}}
+
+	Localize(item, loc)
+
+	if item.CreatedAt.Location() != time.UTC || item.DeletedAt.Location() != time.UTC || item.Children[0].CreatedAt.Location() != time.UTC {
+		t.Errorf("Localize() modified its input: %+v", item)
+	}
+}
+
+func TestLocalizeUTC(t *testing.T) {
+	item := &localizeItem{CreatedAt: time.Now()}
+	if got := Localize(item, time.UTC); got != item {
+		t.Error("Localize() with UTC copied its input, want it returned as is")
+	}
+}
@@ -0,0 +1,121 @@
+package timezone
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	// Embed the IANA database so zones resolve in minimal containers
+	_ "time/tzdata"
+)
+
+// maxOffset is the largest UTC offset in use (UTC+14, Kiribati)
+const maxOffset = 14 * time.Hour
+
+// locations caches loaded IANA zones by name
+var locations sync.Map
+
+// Resolve returns the location for an IANA timezone name such as
+// "Asia/Kolkata", falling back to a fixed UTC offset when the name is
+// empty or unknown. The offset is minutes east of UTC ("330", "-300") or
+// "±HH:MM". UTC is returned when neither is usable.
+func Resolve(name, offset string) *time.Location {
+	if loc, ok := Load(name); ok {
+		return loc
+	}
+	if d, ok := ParseOffset(offset); ok {
+		if d == 0 {
+			return time.UTC
+		}
+		return time.FixedZone(formatOffset(d), int(d.Seconds()))
+	}
+	return time.UTC
+}
+
+// Load returns the IANA zone called name. "Local" and "" are rejected so a
+// client cannot select the server's own zone.
+func Load(name string) (*time.Location, bool) {
+	name = strings.TrimSpace(name)
+	if name == "" || name == "Local" {
+		return nil, false
+	}
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location), true
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, false
+	}
+	locations.Store(name, loc)
+	return loc, true
+}
+
+// ParseOffset parses minutes east of UTC or "±HH:MM"
+func ParseOffset(offset string) (time.Duration, bool) {
+	offset = strings.TrimSpace(offset)
+	if offset == "" {
+		return 0, false
+	}
+
+	var d time.Duration
+	if hours, minutes, ok := strings.Cut(offset, ":"); ok {
+		h, err := strconv.Atoi(hours)
+		if err != nil || len(minutes) != 2 {
+			return 0, false
+		}
+		m, err := strconv.Atoi(minutes)
+		if err != nil || m < 0 || m > 59 {
+			return 0, false
+		}
+		d = time.Duration(h)*time.Hour + time.Duration(m)*time.Minute
+		if strings.HasPrefix(hours, "-") {
+			d = time.Duration(h)*time.Hour - time.Duration(m)*time.Minute
+		}
+	} else {
+		m, err := strconv.Atoi(offset)
+		if err != nil {
+			return 0, false
+		}
+		d = time.Duration(m) * time.Minute
+	}
+
+	if d > maxOffset || d < -maxOffset {
+		return 0, false
+	}
+	return d, true
+}
+
+// formatOffset names a fixed zone, e.g. "UTC+05:30"
+func formatOffset(d time.Duration) string {
+	sign := "+"
+	if d < 0 {
+		sign, d = "-", -d
+	}
+	return fmt.Sprintf("UTC%s%02d:%02d", sign, int(d.Hours()), int(d.Minutes())%60)
+}
+
+// StartOfDay returns midnight of t's calendar day in loc
+func StartOfDay(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+}
+
+// EndOfDay returns the last nanosecond of t's calendar day in loc
+func EndOfDay(t time.Time, loc *time.Location) time.Time {
+	return StartOfDay(t, loc).AddDate(0, 0, 1).Add(-time.Nanosecond)
+}
+
+// DayRange returns the start of t's day in loc and the start of the next
+// day, for queries such as {"$gte": start, "$lt": end}
+func DayRange(t time.Time, loc *time.Location) (time.Time, time.Time) {
+	start := StartOfDay(t, loc)
+	return start, start.AddDate(0, 0, 1)
+}
+
+// Today returns the start of the current day in loc
+func Today(loc *time.Location) time.Time {
+	return StartOfDay(time.Now(), loc)
+}
@@ -0,0 +1,109 @@
+package timezone
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseOffset(t *testing.T) {
+	tests := []struct {
+		offset string
+		want   time.Duration
+		ok     bool
+	}{
+		{offset: "330", want: 5*time.Hour + 30*time.Minute, ok: true},
+		{offset: "-300", want: -5 * time.Hour, ok: true},
+		{offset: " 0 ", want: 0, ok: true},
+		{offset: "+05:30", want: 5*time.Hour + 30*time.Minute, ok: true},
+		{offset: "-03:30", want: -3*time.Hour - 30*time.Minute, ok: true},
+		{offset: "-00:30", want: -30 * time.Minute, ok: true},
+		{offset: "+14:00", want: 14 * time.Hour, ok: true},
+		{offset: "-840", want: -14 * time.Hour, ok: true},
+		{offset: ""},
+		{offset: "abc"},
+		{offset: "+14:01"},
+		{offset: "900"},
+		{offset: "05:60"},
+		{offset: "05:3"},
+		{offset: "xx:30"},
+		{offset: "05:-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.offset, func(t *testing.T) {
+			got, ok := ParseOffset(tt.offset)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("ParseOffset(%q) = %v, %v, want %v, %v", tt.offset, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		name       string
+		zone       string
+		offset     string
+		wantName   string
+		wantOffset int
+	}{
+		{name: "IANA zone", zone: "Asia/Kolkata", wantName: "Asia/Kolkata", wantOffset: 19800},
+		{name: "zone wins over offset", zone: "Asia/Kolkata", offset: "-300", wantName: "Asia/Kolkata", wantOffset: 19800},
+		{name: "unknown zone falls back to offset", zone: "Mars/Olympus", offset: "-300", wantName: "UTC-05:00", wantOffset: -18000},
+		{name: "Local is rejected", zone: "Local", offset: "+05:45", wantName: "UTC+05:45", wantOffset: 20700},
+		{name: "zero offset is UTC", offset: "0", wantName: "UTC"},
+		{name: "nothing usable", zone: "nowhere", offset: "later", wantName: "UTC"},
+	}
+
+	// A fixed instant, so zones with daylight saving do not make the test flaky
+	at := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			loc := Resolve(tt.zone, tt.offset)
+			_, offset := at.In(loc).Zone()
+			if loc.String() != tt.wantName || offset != tt.wantOffset {
+				t.Errorf("Resolve(%q, %q) = %s %+d, want %s %+d", tt.zone, tt.offset, loc, offset, tt.wantName, tt.wantOffset)
+			}
+		})
+	}
+}
+
+func TestDayRange(t *testing.T) {
+	kolkata, _ := Load("Asia/Kolkata")
+	newYork, _ := Load("America/New_York")
+
+	tests := []struct {
+		name      string
+		at        time.Time
+		loc       *time.Location
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{
+			name:      "UTC evening is the next day in Kolkata",
+			at:        time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC),
+			loc:       kolkata,
+			wantStart: time.Date(2024, 5, 1, 18, 30, 0, 0, time.UTC),
+			wantEnd:   time.Date(2024, 5, 2, 18, 30, 0, 0, time.UTC),
+		},
+		{
+			name:      "daylight saving day is 23 hours long",
+			at:        time.Date(2024, 3, 10, 12, 0, 0, 0, newYork),
+			loc:       newYork,
+			wantStart: time.Date(2024, 3, 10, 5, 0, 0, 0, time.UTC),
+			wantEnd:   time.Date(2024, 3, 11, 4, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := DayRange(tt.at, tt.loc)
+			if !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+				t.Errorf("DayRange() = %v, %v, want %v, %v", start, end, tt.wantStart, tt.wantEnd)
+			}
+			if got := EndOfDay(tt.at, tt.loc); !got.Equal(tt.wantEnd.Add(-time.Nanosecond)) {
+				t.Errorf("EndOfDay() = %v, want %v", got, tt.wantEnd.Add(-time.Nanosecond))
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package validator
+
+import (
+	"net/mail"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// FieldError is a struct field that failed a `validate` rule
+type FieldError struct {
+	// Field is the JSON name of the field
+	Field string
+
+	// Rule is the failed rule, e.g. "required" or "min"
+	Rule string
+
+	// Param is the rule argument, e.g. "8" for min=8
+	Param string
+}
+
+// Struct checks the `validate` tags of a struct (or pointer to one) and
+// returns every field that fails, in field order. Supported rules:
+//
+//	required        non-zero value, non-empty string/slice/map
+//	email           a valid email address (empty values pass; combine with required)
+//	min=N, max=N    string length, slice length or numeric value
+//	oneof=a b c     one of the space separated values
+func Struct(v interface{}) []FieldError {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var errs []FieldError
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		tag := sf.Tag.Get("validate")
+		if tag == "" || !sf.IsExported() {
+			continue
+		}
+
+		field := rv.Field(i)
+		for _, rule := range strings.Split(tag, ",") {
+			name, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+			if !check(field, name, param) {
+				errs = append(errs, FieldError{Field: jsonName(sf), Rule: name, Param: param})
+				break
+			}
+		}
+	}
+	return errs
+}
+
+// check reports whether v passes rule
+func check(v reflect.Value, rule, param string) bool {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return rule != "required"
+		}
+		v = v.Elem()
+	}
+
+	switch rule {
+	case "required":
+		switch v.Kind() {
+		case reflect.String, reflect.Slice, reflect.Map:
+			return v.Len() > 0
+		}
+		return !v.IsZero()
+
+	case "email":
+		if v.Kind() != reflect.String || v.Len() == 0 {
+			return true
+		}
+		addr, err := mail.ParseAddress(v.String())
+		return err == nil && addr.Address == v.String()
+
+	case "min", "max":
+		bound, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return true
+		}
+		var actual float64
+		switch {
+		case v.Kind() == reflect.String || v.Kind() == reflect.Slice || v.Kind() == reflect.Map:
+			actual = float64(v.Len())
+		case v.CanInt():
+			actual = float64(v.Int())
+		case v.CanUint():
+			actual = float64(v.Uint())
+		case v.CanFloat():
+			actual = v.Float()
+		default:
+			return true
+		}
+		if rule == "min" {
+			return actual >= bound
+		}
+		return actual <= bound
+
+	case "oneof":
+		actual := reflectString(v)
+		if actual == "" {
+			return true
+		}
+		for _, option := range strings.Fields(param) {
+			if option == actual {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// reflectString formats strings and integers for oneof
+func reflectString(v reflect.Value) string {
+	switch {
+	case v.CanInt():
+		return strconv.FormatInt(v.Int(), 10)
+	case v.CanUint():
+		return strconv.FormatUint(v.Uint(), 10)
+	}
+	return v.String()
+}
+
+// jsonName returns the name a field is decoded from
+func jsonName(sf reflect.StructField) string {
+	name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+	if name == "" || name == "-" {
+		return sf.Name
+	}
+	return name
+}
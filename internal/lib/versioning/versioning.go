@@ -0,0 +1,131 @@
+package versioning
+
+import (
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/addixit1/fiber-boilerplate/internal/config"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/locale"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/reqctx"
+	"github.com/gofiber/fiber/v2"
+)
+
+// RouteFunc registers a module's routes on the group of one version
+type RouteFunc func(r fiber.Router)
+
+// versionPattern matches a version path segment such as "v2"
+var versionPattern = regexp.MustCompile(`^v[0-9]+$`)
+
+var (
+	mu       sync.RWMutex
+	registry = map[string][]RouteFunc{}
+)
+
+// Register adds module routes to a version, e.g. Register("v1", userv1.Routes).
+// Call it before Mount.
+func Register(version string, routes ...RouteFunc) {
+	mu.Lock()
+	defer mu.Unlock()
+	registry[version] = append(registry[version], routes...)
+}
+
+// Versions returns the registered versions, oldest first
+func Versions() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+
+	versions := make([]string, 0, len(registry))
+	for version := range registry {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		a, _ := strconv.Atoi(strings.TrimPrefix(versions[i], "v"))
+		b, _ := strconv.Atoi(strings.TrimPrefix(versions[j], "v"))
+		return a < b
+	})
+	return versions
+}
+
+// Supported reports whether version has registered routes
+func Supported(version string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	_, ok := registry[version]
+	return ok
+}
+
+// Mount serves every registered version under prefix + "/<version>" and
+// dispatches unversioned requests under prefix by the routeversion header,
+// falling back to apiVersions.default. So with prefix "/api", both
+// GET /api/v2/users and GET /api/users with "routeversion: v2" reach the
+// v2 handlers.
+func Mount(app *fiber.App, prefix string) {
+	app.Use(prefix, dispatch(prefix))
+
+	for _, version := range Versions() {
+		group := app.Group(prefix + "/" + version)
+		mu.RLock()
+		routes := registry[version]
+		mu.RUnlock()
+		for _, register := range routes {
+			register(group)
+		}
+	}
+}
+
+// dispatch resolves the version of a request, rejects unknown versions,
+// rewrites unversioned paths and sets the deprecation headers
+func dispatch(prefix string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		rc := reqctx.From(c)
+		rest := strings.TrimPrefix(c.Path(), prefix)
+
+		segment := strings.TrimPrefix(rest, "/")
+		if i := strings.IndexByte(segment, '/'); i >= 0 {
+			segment = segment[:i]
+		}
+
+		version := segment
+		if !versionPattern.MatchString(segment) {
+			version = strings.TrimSpace(c.Get("routeversion"))
+			if version == "" {
+				version = config.Current().APIVersions.Default
+			}
+			if Supported(version) {
+				c.Path(prefix + "/" + version + rest)
+			}
+		}
+
+		if !Supported(version) {
+			return c.Status(config.BAD_REQUEST).JSON(config.UnsupportedAPIVersion(fiber.Map{
+				"supported": Versions(),
+			}, rc.Lang, locale.Params{"version": version}))
+		}
+
+		rc.RouteVersion = version
+		setDeprecationHeaders(c, version)
+		return c.Next()
+	}
+}
+
+// setDeprecationHeaders announces deprecated versions with the Deprecation,
+// Sunset (RFC 8594) and Link headers
+func setDeprecationHeaders(c *fiber.Ctx, version string) {
+	versions := config.Current().APIVersions
+	sunset, hasSunset := versions.SunsetOf(version)
+	if !versions.IsDeprecated(version) && !hasSunset {
+		return
+	}
+
+	c.Set("Deprecation", "true")
+	if hasSunset {
+		c.Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+	}
+	if versions.Link != "" {
+		c.Set(fiber.HeaderLink, "<"+versions.Link+`>; rel="deprecation"`)
+	}
+}
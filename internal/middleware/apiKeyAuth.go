@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"errors"
+
+	"github.com/addixit1/fiber-boilerplate/internal/config"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/reqctx"
+	"github.com/addixit1/fiber-boilerplate/internal/modules/apikey"
+	"github.com/addixit1/fiber-boilerplate/internal/utils/errortracker"
+	"github.com/gofiber/fiber/v2"
+)
+
+// APIKeyAuth authenticates service-to-service clients by API key, read from
+// the configured header (X-API-Key by default) or the legacy api_key header.
+// When scopes are given the key must grant at least one of them.
+// The key is recorded as the request's principal (see reqctx.PrincipalOf).
+func APIKeyAuth(scopes ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		lang := reqctx.Lang(c)
+
+		raw := c.Get(config.Config.APIKeys.Header)
+		if raw == "" {
+			raw = c.Get("api_key")
+		}
+		if raw == "" {
+			return c.Status(config.UNAUTHORIZED).JSON(config.InvalidAPIKey(lang))
+		}
+
+		key, err := apikey.Verify(c.UserContext(), raw)
+		switch {
+		case errors.Is(err, apikey.ErrExpiredKey):
+			return c.Status(config.UNAUTHORIZED).JSON(config.APIKeyExpired(lang))
+		case errors.Is(err, apikey.ErrInvalidKey):
+			return c.Status(config.UNAUTHORIZED).JSON(config.InvalidAPIKey(lang))
+		case err != nil:
+			errortracker.Track(errortracker.LayerMiddleware, "Failed to verify API key", err)
+			return c.Status(config.INTERNAL_SERVER_ERROR).JSON(config.InternalServerError(lang))
+		}
+
+		if !key.HasScope(scopes...) {
+			return c.Status(config.ACCESS_FORBIDDEN).JSON(config.AccessForbidden(lang))
+		}
+
+		result := apikey.Allow(key)
+		setRateLimitHeaders(c, result)
+		if !result.Allowed {
+			return tooManyRequests(c, lang, result)
+		}
+
+		apikey.RecordUsage(c.UserContext(), key)
+		reqctx.SetPrincipal(c, &reqctx.Principal{Kind: reqctx.PrincipalAPIKey, ID: key.ID.Hex(), Name: key.Name, Scopes: key.Scopes})
+		return c.Next()
+	}
+}
+
+// APIKeyOrBasicAuth serves routes used by both services and admins: requests
+// carrying an API key go through APIKeyAuth, all others through BasicAuth.
+func APIKeyOrBasicAuth(scopes ...string) fiber.Handler {
+	apiKeyAuth := APIKeyAuth(scopes...)
+	basicAuth := BasicAuth(scopes...)
+	return func(c *fiber.Ctx) error {
+		if c.Get(config.Config.APIKeys.Header) != "" || c.Get("api_key") != "" {
+			return apiKeyAuth(c)
+		}
+		return basicAuth(c)
+	}
+}
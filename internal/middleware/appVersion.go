@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"github.com/addixit1/fiber-boilerplate/internal/config"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/reqctx"
+	"github.com/addixit1/fiber-boilerplate/internal/modules/appversion"
+	"github.com/addixit1/fiber-boilerplate/internal/utils/errortracker"
+	"github.com/gofiber/fiber/v2"
+)
+
+// AppVersion enforces the version policy of Android and iOS clients that
+// send an appversion header. Clients below the minimum version get
+// 426 FORCE_UPDATE; clients below the latest version are served normally
+// with X-App-Update: available and X-App-Latest-Version headers.
+// Web clients, unconfigured platforms and policy lookup failures pass.
+func AppVersion() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		rc := reqctx.From(c)
+		if !config.Current().AppVersion.Enabled || rc.Platform == reqctx.PlatformWeb || c.Get("appversion") == "" {
+			return c.Next()
+		}
+
+		status, policy, err := appversion.Check(c.UserContext(), rc.Platform, rc.AppVersion)
+		if err != nil {
+			errortracker.Track(errortracker.LayerMiddleware, "Failed to check app version", err)
+			return c.Next()
+		}
+
+		switch status {
+		case appversion.UpdateRequired:
+			c.Set("X-App-Update", "required")
+			c.Set("X-App-Latest-Version", policy.LatestVersion)
+			return c.Status(config.UPGRADE_REQUIRED).JSON(config.ForceUpdate(fiber.Map{
+				"platform":        rc.Platform.String(),
+				"current_version": rc.AppVersion,
+				"min_version":     policy.MinVersion,
+				"latest_version":  policy.LatestVersion,
+				"update_url":      policy.UpdateURL,
+			}, rc.Lang))
+		case appversion.UpdateAvailable:
+			c.Set("X-App-Update", "available")
+			c.Set("X-App-Latest-Version", policy.LatestVersion)
+		}
+		return c.Next()
+	}
+}
@@ -1,17 +1,93 @@
 package middleware
 
 import (
-	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/basicauth"
-	"github.com/addixit1/fiber-boilerplate/internal/config"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/addixit1/fiber-boilerplate/internal/config"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/basicauth"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/bruteforce"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/reqctx"
+	"github.com/gofiber/fiber/v2"
 )
 
-func BasicAuth() fiber.Handler {
-	return basicauth.New(basicauth.Config{
-		Users: map[string]string{
-			config.BASIC_USERNAME: config.BASIC_PASSWORD,
-		},
-		Realm: "Restricted",
-	})
-}
\ No newline at end of file
+// bruteForceScope namespaces Basic Auth failures from other login flows
+const bruteForceScope = "basicauth"
+
+// BasicAuth authenticates against the configured Basic Auth users.
+// When scopes are given the user must hold at least one of them.
+// The user is recorded as the request's principal (see reqctx.PrincipalOf).
+func BasicAuth(scopes ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		lang := reqctx.Lang(c)
+
+		username, password, ok := parseBasicAuth(c.Get(fiber.HeaderAuthorization))
+		if !ok {
+			return basicAuthChallenge(c, config.UnauthorizedAccess(lang))
+		}
+
+		// Accounts are locked per client IP, so failures elsewhere never lock out the real user
+		status := bruteforce.Check(bruteForceScope, username, c.IP())
+		if status.Locked() {
+			return accountLocked(c, lang, status.RetryAfter)
+		}
+		if status.Throttled() {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(ceilSeconds(status.Delay)))
+			return c.Status(config.TOO_MANY_REQUESTS).JSON(config.TooManyRequests(lang))
+		}
+
+		user, ok := basicauth.Authenticate(username, password)
+		if !ok {
+			status := bruteforce.Fail(bruteForceScope, username, c.IP())
+			if status.Locked() {
+				return accountLocked(c, lang, status.RetryAfter)
+			}
+			if status.Throttled() {
+				c.Set(fiber.HeaderRetryAfter, strconv.Itoa(ceilSeconds(status.Delay)))
+			}
+			if status.CaptchaRequired {
+				return basicAuthChallenge(c, config.CaptchaRequired(lang))
+			}
+			return basicAuthChallenge(c, config.UnauthorizedAccess(lang))
+		}
+		bruteforce.Succeed(bruteForceScope, username, c.IP())
+
+		if !user.HasScope(scopes...) {
+			return c.Status(config.ACCESS_FORBIDDEN).JSON(config.AccessForbidden(lang))
+		}
+
+		reqctx.SetPrincipal(c, &reqctx.Principal{Kind: reqctx.PrincipalBasic, ID: user.Username, Name: user.Username, Scopes: user.Scopes})
+		return c.Next()
+	}
+}
+
+// parseBasicAuth decodes an "Authorization: Basic ..." header
+func parseBasicAuth(header string) (string, string, bool) {
+	const prefix = "basic "
+	if len(header) <= len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return "", "", false
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(header[len(prefix):])
+	if err != nil {
+		return "", "", false
+	}
+
+	username, password, ok := strings.Cut(string(decoded), ":")
+	if !ok || username == "" {
+		return "", "", false
+	}
+	return username, password, true
+}
+
+func basicAuthChallenge(c *fiber.Ctx, response config.APIResponse) error {
+	c.Set(fiber.HeaderWWWAuthenticate, `Basic realm="`+config.Config.BasicAuth.Realm+`", charset="UTF-8"`)
+	return c.Status(config.UNAUTHORIZED).JSON(response)
+}
+
+func accountLocked(c *fiber.Ctx, lang string, retryAfter time.Duration) error {
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(ceilSeconds(retryAfter)))
+	return c.Status(config.TOO_MANY_REQUESTS).JSON(config.AccountLocked(lang))
+}
@@ -2,11 +2,13 @@ package middleware
 
 import (
 	"github.com/addixit1/fiber-boilerplate/internal/config"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/reqctx"
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-
+// JWTAuth validates the bearer token in the Authorization header.
+// The token's user_id claim is recorded as the request's principal.
 func JWTAuth() fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		tokenString := c.Get("Authorization")
@@ -14,14 +16,23 @@ func JWTAuth() fiber.Handler {
 			return fiber.ErrUnauthorized
 		}
 
-		token, err := jwt.Parse(tokenString, func(t *jwt.Token) (any, error) {
-			return []byte(config.Config.JWTSecret), nil
-		})
+		// Accept the previous key for a grace period after a rotation
+		for _, secret := range config.JWTKey.Candidates() {
+			token, err := jwt.Parse(tokenString, func(t *jwt.Token) (any, error) {
+				return []byte(secret), nil
+			}, jwt.WithValidMethods([]string{"HS256", "HS384", "HS512"}))
 
-		if err != nil || !token.Valid {
-			return fiber.ErrUnauthorized
+			if err == nil && token.Valid {
+				principal := &reqctx.Principal{Kind: reqctx.PrincipalBearer}
+				if claims, ok := token.Claims.(jwt.MapClaims); ok {
+					principal.ID, _ = claims["user_id"].(string)
+					principal.Name, _ = claims["email"].(string)
+				}
+				reqctx.SetPrincipal(c, principal)
+				return c.Next()
+			}
 		}
 
-		return c.Next()
+		return fiber.ErrUnauthorized
 	}
 }
@@ -3,8 +3,12 @@ package middleware
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/addixit1/fiber-boilerplate/internal/config"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/reqctx"
+	"github.com/addixit1/fiber-boilerplate/internal/modules/apikey"
 	"github.com/gofiber/fiber/v2"
 )
 
@@ -60,17 +64,27 @@ func DetailedLogger() fiber.Handler {
 			fmt.Printf("Request Query=====> {Object: null prototype} {}\n")
 		}
 
-		// Print authorization header
-		auth := c.Get("Authorization", "")
-		if auth != "" {
-			if len(auth) > 6 {
-				fmt.Printf("Authorization=====> %s\n", auth)
-			}
+		// Print authorization scheme; the credentials are never logged
+		if auth := c.Get("Authorization", ""); auth != "" {
+			scheme, _, _ := strings.Cut(auth, " ")
+			fmt.Printf("Authorization=====> %s ***\n", scheme)
+		}
+
+		// Print request ID and authenticated principal
+		rc := reqctx.From(c)
+		fmt.Printf("request_id========> %s\n", rc.RequestID)
+		if rc.Principal != nil {
+			fmt.Printf("principal=========> %s:%s\n", rc.Principal.Kind, rc.Principal.ID)
 		}
 
-		// Print API key
-		apiKey := c.Get("api_key", "1234")
-		fmt.Printf("api_key===========> %s\n", apiKey)
+		// Print API key, masked to its public prefix
+		apiKey := c.Get(config.Config.APIKeys.Header)
+		if apiKey == "" {
+			apiKey = c.Get("api_key")
+		}
+		if apiKey != "" {
+			fmt.Printf("api_key===========> %s\n", apikey.MaskSecret(apiKey))
+		}
 
 		// Print headers
 		fmt.Printf("platform==========> %s\n", c.Get("platform", "1"))
@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"github.com/addixit1/fiber-boilerplate/internal/config"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Feature hides the routes behind it with 404 unless the named feature flag
+// is enabled. Flags are hot reloadable, so routes can be switched on and
+// off without a restart.
+func Feature(name string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if !config.Current().Feature(name) {
+			return fiber.ErrNotFound
+		}
+		return c.Next()
+	}
+}
@@ -1,40 +1,60 @@
 package middleware
 
 import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/addixit1/fiber-boilerplate/internal/config"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/locale"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/reqctx"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/timezone"
 	"github.com/gofiber/fiber/v2"
 )
 
-// RequestHeaders middleware extracts common headers from request
+// requestIDPattern limits client supplied request IDs to safe log values
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestHeaders builds the typed reqctx.RequestContext from the request
+// headers (language, platform, timezone/offset, app and route version,
+// request ID). Missing headers get defaults; a malformed platform header
+// is rejected with 400.
 func RequestHeaders() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		// Extract and store headers in context
+		rc := reqctx.New()
+		reqctx.Set(c, rc)
 
-		// Language (already handled by Language middleware, but keeping for completeness)
-		lang := c.Get("accept-language", "en")
-		if len(lang) > 2 {
-			lang = lang[:2]
+		// Request ID, echoed back for correlating logs
+		if id := c.Get(fiber.HeaderXRequestID); requestIDPattern.MatchString(id) {
+			rc.RequestID = id
 		}
-		c.Locals("lang", lang)
-
-		// Platform: 1-Android, 2-iOS, 3-WEB
-		platform := c.Get("platform", "3")
-		c.Locals("platform", platform)
+		c.Set(fiber.HeaderXRequestID, rc.RequestID)
 
-		// Timezone
-		timezone := c.Get("timezone", "Asia/Kolkata")
-		c.Locals("timezone", timezone)
+		// Language, negotiated the same way as the Language middleware
+		setLang(c)
 
-		// Timezone Offset
-		offset := c.Get("offset", "0")
-		c.Locals("offset", offset)
+		// Platform: 1-Android, 2-iOS, 3-WEB
+		if raw := c.Get("platform"); raw != "" {
+			platform, err := strconv.Atoi(raw)
+			if err != nil || !reqctx.Platform(platform).Valid() {
+				return c.Status(config.BAD_REQUEST).JSON(config.ErrorWithParams("INVALID_HEADER", locale.Params{"header": "platform"}, rc.Lang))
+			}
+			rc.Platform = reqctx.Platform(platform)
+		}
 
-		// App Version
-		appversion := c.Get("appversion", "v1")
-		c.Locals("appversion", appversion)
+		// Timezone, falling back to the offset header; UTC when neither is usable
+		rc.Location = timezone.Resolve(c.Get("timezone"), c.Get("offset"))
+		_, offset := time.Now().In(rc.Location).Zone()
+		rc.Offset = time.Duration(offset) * time.Second
 
-		// Route Version
-		routeversion := c.Get("routeversion", "v1")
-		c.Locals("routeversion", routeversion)
+		// App and route version
+		if version := strings.TrimSpace(c.Get("appversion")); version != "" {
+			rc.AppVersion = version
+		}
+		if version := strings.TrimSpace(c.Get("routeversion")); version != "" {
+			rc.RouteVersion = version
+		}
 
 		return c.Next()
 	}
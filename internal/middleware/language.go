@@ -1,28 +1,37 @@
 package middleware
 
 import (
+	"github.com/addixit1/fiber-boilerplate/internal/lib/locale"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/reqctx"
 	"github.com/gofiber/fiber/v2"
 )
 
-// Language middleware extracts language from request headers
+// Language middleware negotiates the response language and stores it in
+// the request context (see reqctx.Lang). A supported "lang" query
+// parameter or cookie overrides the Accept-Language header. The chosen
+// language is sent back in the Content-Language header.
 func Language() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		// Try to get language from Accept-Language header
-		lang := c.Get("Accept-Language", "en")
+		setLang(c)
+		return c.Next()
+	}
+}
 
-		// If empty or not supported, default to English
-		if lang == "" {
-			lang = "en"
-		}
+// setLang stores the negotiated language and sets Content-Language
+func setLang(c *fiber.Ctx) string {
+	lang := negotiateLang(c)
+	reqctx.From(c).Lang = lang
+	c.Set(fiber.HeaderContentLanguage, lang)
+	return lang
+}
 
-		// Take only first 2 characters (e.g., "en-US" -> "en")
-		if len(lang) > 2 {
-			lang = lang[:2]
+// negotiateLang picks the language from the lang query parameter, the lang
+// cookie or the Accept-Language header, in that order
+func negotiateLang(c *fiber.Ctx) string {
+	for _, override := range []string{c.Query("lang"), c.Cookies("lang")} {
+		if lang, ok := locale.Match(override); ok {
+			return lang
 		}
-
-		// Store language in context for use in handlers
-		c.Locals("lang", lang)
-
-		return c.Next()
 	}
+	return locale.Negotiate(c.Get(fiber.HeaderAcceptLanguage))
 }
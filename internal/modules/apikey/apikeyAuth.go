@@ -0,0 +1,163 @@
+package apikey
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/addixit1/fiber-boilerplate/internal/config"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/ratelimit"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/redis"
+	"github.com/addixit1/fiber-boilerplate/internal/querybuilder"
+	"github.com/addixit1/fiber-boilerplate/internal/utils/errortracker"
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// keyPrefix marks strings as API keys of this service, e.g. in secret scanners
+const keyPrefix = "fbk"
+
+// lastUsedResolution limits how often last_used_at is written per key
+const lastUsedResolution = time.Minute
+
+var (
+	// ErrInvalidKey is returned for unknown, malformed or revoked keys
+	ErrInvalidKey = errors.New("invalid API key")
+
+	// ErrExpiredKey is returned for keys past their expiry
+	ErrExpiredKey = errors.New("API key expired")
+
+	// ErrRevokedKey is returned when changing a key that was revoked
+	ErrRevokedKey = errors.New("API key revoked")
+)
+
+var repo = querybuilder.NewBaseRepository()
+
+// GenerateSecret returns a new random secret of the form fbk_<prefix>_<secret>
+// along with its public prefix and the hash to store
+func GenerateSecret() (secret, prefix, hash string, err error) {
+	idBytes := make([]byte, 4)
+	secretBytes := make([]byte, 32)
+	if _, err = rand.Read(idBytes); err != nil {
+		return "", "", "", err
+	}
+	if _, err = rand.Read(secretBytes); err != nil {
+		return "", "", "", err
+	}
+
+	prefix = hex.EncodeToString(idBytes)
+	secret = keyPrefix + "_" + prefix + "_" + base64.RawURLEncoding.EncodeToString(secretBytes)
+	return secret, prefix, HashSecret(secret), nil
+}
+
+// HashSecret returns the stored representation of a secret
+func HashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// MaskSecret returns a loggable form of a raw key, keeping only its public prefix
+func MaskSecret(raw string) string {
+	parts := strings.SplitN(raw, "_", 3)
+	if len(parts) != 3 || parts[0] != keyPrefix {
+		return "***"
+	}
+	return parts[0] + "_" + parts[1] + "_***"
+}
+
+// Verify looks up a raw key and checks its secret, revocation and expiry
+func Verify(ctx context.Context, raw string) (*APIKey, error) {
+	parts := strings.SplitN(raw, "_", 3)
+	if len(parts) != 3 || parts[0] != keyPrefix {
+		return nil, ErrInvalidKey
+	}
+
+	key := &APIKey{}
+	err := repo.FindOne(ctx, key, bson.M{"prefix": parts[1]}, nil)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, ErrInvalidKey
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(key.Hash), []byte(HashSecret(raw))) != 1 || key.IsRevoked() {
+		return nil, ErrInvalidKey
+	}
+	if key.IsExpired() {
+		return nil, ErrExpiredKey
+	}
+	return key, nil
+}
+
+// Allow applies the key's per-minute rate limit
+func Allow(key *APIKey) ratelimit.Result {
+	return ratelimit.Allow("apikey:"+key.ID.Hex(), ratelimit.Limit{
+		Max:       key.Limit(),
+		Window:    time.Minute,
+		Algorithm: ratelimit.SlidingWindow,
+	})
+}
+
+// RecordUsage increments the key's total and daily usage counters and
+// refreshes last_used_at at most once per lastUsedResolution, within the
+// request's ctx
+func RecordUsage(ctx context.Context, key *APIKey) {
+	id := key.ID.Hex()
+	day := time.Now().UTC().Format("2006-01-02")
+
+	if _, err := redis.Client.Incr(ctx, "apikey:usage:"+id).Result(); err != nil {
+		errortracker.Track(errortracker.LayerMiddleware, "Failed to record API key usage", err)
+	}
+	if _, err := redis.IncrWithTTL("apikey:usage:"+id+":"+day, config.Config.APIKeys.UsageRetention); err != nil {
+		errortracker.Track(errortracker.LayerMiddleware, "Failed to record API key daily usage", err)
+	}
+
+	if key.LastUsedAt != nil && time.Since(*key.LastUsedAt) < lastUsedResolution {
+		return
+	}
+
+	// Bookkeeping, kept out of the audit trail
+	_, err := repo.UpdateOne(querybuilder.SkipHooks(ctx), &APIKey{}, bson.M{"_id": key.ID}, bson.M{"$set": bson.M{"last_used_at": time.Now()}})
+	if err != nil {
+		errortracker.Track(errortracker.LayerRepository, "Failed to update API key last_used_at", err)
+	}
+}
+
+// Usage returns the total request count and the daily counts for the last days
+func Usage(key *APIKey, days int) (int64, map[string]int64) {
+	id := key.ID.Hex()
+	daily := make(map[string]int64, days)
+
+	total, err := redis.Client.Get(redis.Ctx, "apikey:usage:"+id).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		errortracker.Track(errortracker.LayerService, "Failed to read API key usage", err)
+	}
+
+	for i := 0; i < days; i++ {
+		day := time.Now().UTC().AddDate(0, 0, -i).Format("2006-01-02")
+		count, err := redis.Client.Get(redis.Ctx, "apikey:usage:"+id+":"+day).Int64()
+		if err != nil && !errors.Is(err, redis.Nil) {
+			errortracker.Track(errortracker.LayerService, "Failed to read API key daily usage", err)
+		}
+		daily[day] = count
+	}
+	return total, daily
+}
+
+// EnsureIndexes creates the unique prefix index used to look keys up
+func EnsureIndexes(ctx context.Context) error {
+	_, err := mgm.Coll(&APIKey{}).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "prefix", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "owner", Value: 1}}},
+	})
+	return err
+}
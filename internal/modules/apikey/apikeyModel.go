@@ -0,0 +1,65 @@
+package apikey
+
+import (
+	"time"
+
+	"github.com/addixit1/fiber-boilerplate/internal/config"
+	"github.com/kamva/mgm/v3"
+)
+
+// APIKey model with MGM integration.
+// Only a SHA-256 hash of the secret is stored; the secret itself is shown once.
+type APIKey struct {
+	// MGM's DefaultModel includes: ID, CreatedAt, UpdatedAt
+	mgm.DefaultModel `bson:",inline"`
+	Name             string     `bson:"name" json:"name"`
+	Owner            string     `bson:"owner" json:"owner"`
+	Prefix           string     `bson:"prefix" json:"prefix"`
+	Hash             string     `bson:"hash" json:"-"`
+	Scopes           []string   `bson:"scopes" json:"scopes"`
+	RateLimit        int        `bson:"rate_limit" json:"rate_limit"`
+	ExpiresAt        *time.Time `bson:"expires_at,omitempty" json:"expires_at,omitempty"`
+	LastUsedAt       *time.Time `bson:"last_used_at,omitempty" json:"last_used_at,omitempty"`
+	RevokedAt        *time.Time `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+}
+
+// CollectionName returns the MongoDB collection name for APIKey model
+func (APIKey) CollectionName() string {
+	return config.API_KEYS_COLLECTION
+}
+
+// IsExpired reports whether the key is past its expiry
+func (k *APIKey) IsExpired() bool {
+	return k.ExpiresAt != nil && time.Now().After(*k.ExpiresAt)
+}
+
+// IsRevoked reports whether the key has been revoked
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
+
+// HasScope reports whether the key grants any of scopes ("*" grants all)
+func (k *APIKey) HasScope(scopes ...string) bool {
+	if len(scopes) == 0 {
+		return true
+	}
+	for _, granted := range k.Scopes {
+		if granted == "*" {
+			return true
+		}
+		for _, required := range scopes {
+			if granted == required {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Limit returns the key's requests-per-minute limit
+func (k *APIKey) Limit() int {
+	if k.RateLimit > 0 {
+		return k.RateLimit
+	}
+	return config.Config.APIKeys.DefaultRateLimit
+}
@@ -0,0 +1,167 @@
+package apikeyv1
+
+import (
+	"errors"
+
+	"github.com/addixit1/fiber-boilerplate/internal/config"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/reqctx"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/timezone"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/validator"
+	"github.com/addixit1/fiber-boilerplate/internal/modules/apikey"
+	"github.com/addixit1/fiber-boilerplate/internal/utils/errortracker"
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// isNotFound reports whether err means the requested key does not exist
+func isNotFound(err error) bool {
+	return errors.Is(err, mongo.ErrNoDocuments) || errors.Is(err, primitive.ErrInvalidHex)
+}
+
+// List godoc
+// @Summary List API keys
+// @Description Get all API keys, optionally filtered by owner
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Param owner query string false "Filter by owner"
+// @Param accept-language header string false "Language: en, hi" Enums(en,hi) default(en)
+// @Security basicAuth
+// @Success 200 {object} config.APIResponse
+// @Failure 401 {object} config.APIResponse
+// @Failure 403 {object} config.APIResponse
+// @Router /api-keys [get]
+func List(c *fiber.Ctx) error {
+	lang := reqctx.Lang(c)
+
+	keys, err := ListAPIKeys(c.Query("owner"))
+	if err != nil {
+		errortracker.Track(errortracker.LayerController, "Failed to list API keys", err)
+		return c.Status(500).JSON(config.InternalServerError(lang))
+	}
+
+	return timezone.JSON(c.Status(200), config.List(keys, lang))
+}
+
+// Get godoc
+// @Summary Get an API key
+// @Description Get an API key with its usage over the last 7 days
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Param id path string true "API key ID"
+// @Param accept-language header string false "Language: en, hi" Enums(en,hi) default(en)
+// @Security basicAuth
+// @Success 200 {object} config.APIResponse
+// @Failure 401 {object} config.APIResponse
+// @Failure 404 {object} config.APIResponse
+// @Router /api-keys/{id} [get]
+func Get(c *fiber.Ctx) error {
+	lang := reqctx.Lang(c)
+
+	details, err := GetAPIKey(c.UserContext(), c.Params("id"))
+	if isNotFound(err) {
+		return c.Status(404).JSON(config.APIKeyNotFound(lang))
+	}
+	if err != nil {
+		errortracker.Track(errortracker.LayerController, "Failed to get API key", err)
+		return c.Status(500).JSON(config.InternalServerError(lang))
+	}
+
+	return timezone.JSON(c.Status(200), config.Details(details, lang))
+}
+
+// Create godoc
+// @Summary Create an API key
+// @Description Create an API key. The secret is only returned in this response.
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Param body body CreateAPIKeyDTO true "Create API key"
+// @Param accept-language header string false "Language: en, hi" Enums(en,hi) default(en)
+// @Security basicAuth
+// @Success 201 {object} config.APIResponse
+// @Failure 400 {object} config.APIResponse
+// @Failure 401 {object} config.APIResponse
+// @Router /api-keys [post]
+func Create(c *fiber.Ctx) error {
+	lang := reqctx.Lang(c)
+
+	var keyData CreateAPIKeyDTO
+	if err := c.BodyParser(&keyData); err != nil {
+		errortracker.Track(errortracker.LayerController, "Failed to parse request body", err)
+		return c.Status(400).JSON(config.Error("INVALID_REQUEST_BODY", lang))
+	}
+	if errs := validator.Struct(&keyData); len(errs) > 0 {
+		return c.Status(400).JSON(config.ValidationError(errs, lang))
+	}
+
+	created, err := CreateAPIKey(c.UserContext(), &keyData)
+	if err != nil {
+		errortracker.Track(errortracker.LayerController, "Failed to create API key", err)
+		return c.Status(500).JSON(config.InternalServerError(lang))
+	}
+
+	return timezone.JSON(c.Status(201), config.APIKeyCreated(created, lang))
+}
+
+// Rotate godoc
+// @Summary Rotate an API key
+// @Description Issue a new secret for an API key. The old secret stops working immediately. Revoked keys cannot be rotated.
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Param id path string true "API key ID"
+// @Param accept-language header string false "Language: en, hi" Enums(en,hi) default(en)
+// @Security basicAuth
+// @Success 200 {object} config.APIResponse
+// @Failure 401 {object} config.APIResponse
+// @Failure 404 {object} config.APIResponse
+// @Failure 409 {object} config.APIResponse
+// @Router /api-keys/{id}/rotate [post]
+func Rotate(c *fiber.Ctx) error {
+	lang := reqctx.Lang(c)
+
+	rotated, err := RotateAPIKey(c.UserContext(), c.Params("id"))
+	if isNotFound(err) {
+		return c.Status(404).JSON(config.APIKeyNotFound(lang))
+	}
+	if errors.Is(err, apikey.ErrRevokedKey) {
+		return c.Status(409).JSON(config.APIKeyAlreadyRevoked(lang))
+	}
+	if err != nil {
+		errortracker.Track(errortracker.LayerController, "Failed to rotate API key", err)
+		return c.Status(500).JSON(config.InternalServerError(lang))
+	}
+
+	return timezone.JSON(c.Status(200), config.APIKeyRotated(rotated, lang))
+}
+
+// Revoke godoc
+// @Summary Revoke an API key
+// @Description Revoke an API key. Revoked keys are kept for auditing.
+// @Tags API Keys
+// @Accept json
+// @Produce json
+// @Param id path string true "API key ID"
+// @Param accept-language header string false "Language: en, hi" Enums(en,hi) default(en)
+// @Security basicAuth
+// @Success 200 {object} config.APIResponse
+// @Failure 401 {object} config.APIResponse
+// @Failure 404 {object} config.APIResponse
+// @Router /api-keys/{id} [delete]
+func Revoke(c *fiber.Ctx) error {
+	lang := reqctx.Lang(c)
+
+	err := RevokeAPIKey(c.UserContext(), c.Params("id"))
+	if isNotFound(err) {
+		return c.Status(404).JSON(config.APIKeyNotFound(lang))
+	}
+	if err != nil {
+		errortracker.Track(errortracker.LayerController, "Failed to revoke API key", err)
+		return c.Status(500).JSON(config.InternalServerError(lang))
+	}
+
+	return c.Status(200).JSON(config.APIKeyRevoked(lang))
+}
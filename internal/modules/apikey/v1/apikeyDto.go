@@ -0,0 +1,29 @@
+package apikeyv1
+
+import (
+	"time"
+
+	"github.com/addixit1/fiber-boilerplate/internal/modules/apikey"
+)
+
+// CreateAPIKeyDTO for creating a new API key
+type CreateAPIKeyDTO struct {
+	Name      string     `json:"name" validate:"required" example:"billing-service"`
+	Owner     string     `json:"owner" validate:"required" example:"billing-team"`
+	Scopes    []string   `json:"scopes" example:"users:read"`
+	RateLimit int        `json:"rate_limit" validate:"min=0" example:"120"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" example:"2027-01-01T00:00:00Z"`
+}
+
+// APIKeySecretDTO is returned once, when a key is created or rotated
+type APIKeySecretDTO struct {
+	APIKey *apikey.APIKey `json:"api_key"`
+	Secret string         `json:"secret" example:"fbk_1a2b3c4d_..."`
+}
+
+// APIKeyDetailsDTO is an API key with its usage counters
+type APIKeyDetailsDTO struct {
+	APIKey     *apikey.APIKey   `json:"api_key"`
+	TotalUsage int64            `json:"total_usage" example:"1024"`
+	DailyUsage map[string]int64 `json:"daily_usage"`
+}
@@ -0,0 +1,60 @@
+package apikeyv1
+
+import (
+	"context"
+	"time"
+
+	"github.com/addixit1/fiber-boilerplate/internal/modules/apikey"
+	"github.com/addixit1/fiber-boilerplate/internal/querybuilder"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var repo = querybuilder.NewBaseRepository()
+
+// FindAPIKeys retrieves API keys matching filter, newest first
+func FindAPIKeys(filter bson.M) ([]apikey.APIKey, error) {
+	ctx := context.Background()
+	keys := []apikey.APIKey{}
+
+	opts := &querybuilder.FindOptions{Sort: bson.M{"created_at": -1}}
+	if err := repo.Find(ctx, &apikey.APIKey{}, &keys, filter, opts); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// FindAPIKeyById retrieves an API key by ID
+func FindAPIKeyById(ctx context.Context, id string) (*apikey.APIKey, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+
+	found := &apikey.APIKey{}
+	if err := repo.FindById(ctx, found, objectID); err != nil {
+		return nil, err
+	}
+
+	return found, nil
+}
+
+// saveAPIKey creates a new API key
+func saveAPIKey(ctx context.Context, key *apikey.APIKey) error {
+	return repo.Save(ctx, key)
+}
+
+// updateAPIKeySecret replaces the stored prefix and hash of a key
+func updateAPIKeySecret(ctx context.Context, key *apikey.APIKey, prefix, hash string) error {
+	key.Prefix = prefix
+	key.Hash = hash
+	return repo.UpdateById(ctx, key)
+}
+
+// revokeAPIKey marks a key as revoked
+func revokeAPIKey(ctx context.Context, key *apikey.APIKey) error {
+	now := time.Now()
+	key.RevokedAt = &now
+	return repo.UpdateById(ctx, key)
+}
@@ -0,0 +1,16 @@
+package apikeyv1
+
+import (
+	"github.com/addixit1/fiber-boilerplate/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+func Routes(r fiber.Router) {
+	admin := middleware.BasicAuth("apikeys:admin")
+
+	r.Post("/api-keys", admin, Create)
+	r.Get("/api-keys", admin, List)
+	r.Get("/api-keys/:id", admin, Get)
+	r.Post("/api-keys/:id/rotate", admin, Rotate)
+	r.Delete("/api-keys/:id", admin, Revoke)
+}
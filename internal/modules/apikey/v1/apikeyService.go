@@ -0,0 +1,84 @@
+package apikeyv1
+
+import (
+	"context"
+
+	"github.com/addixit1/fiber-boilerplate/internal/modules/apikey"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// usageDays is the number of daily usage counters returned for a key
+const usageDays = 7
+
+func ListAPIKeys(owner string) ([]apikey.APIKey, error) {
+	filter := bson.M{}
+	if owner != "" {
+		filter["owner"] = owner
+	}
+	return FindAPIKeys(filter)
+}
+
+func GetAPIKey(ctx context.Context, id string) (*APIKeyDetailsDTO, error) {
+	key, err := FindAPIKeyById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	total, daily := apikey.Usage(key, usageDays)
+	return &APIKeyDetailsDTO{APIKey: key, TotalUsage: total, DailyUsage: daily}, nil
+}
+
+func CreateAPIKey(ctx context.Context, data *CreateAPIKeyDTO) (*APIKeySecretDTO, error) {
+	secret, prefix, hash, err := apikey.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	key := &apikey.APIKey{
+		Name:      data.Name,
+		Owner:     data.Owner,
+		Prefix:    prefix,
+		Hash:      hash,
+		Scopes:    data.Scopes,
+		RateLimit: data.RateLimit,
+		ExpiresAt: data.ExpiresAt,
+	}
+	if err := saveAPIKey(ctx, key); err != nil {
+		return nil, err
+	}
+
+	return &APIKeySecretDTO{APIKey: key, Secret: secret}, nil
+}
+
+// RotateAPIKey issues a new secret for a key; the old secret stops working
+// immediately. Revoked keys cannot be rotated back to life.
+func RotateAPIKey(ctx context.Context, id string) (*APIKeySecretDTO, error) {
+	key, err := FindAPIKeyById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if key.IsRevoked() {
+		return nil, apikey.ErrRevokedKey
+	}
+
+	secret, prefix, hash, err := apikey.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if err := updateAPIKeySecret(ctx, key, prefix, hash); err != nil {
+		return nil, err
+	}
+
+	return &APIKeySecretDTO{APIKey: key, Secret: secret}, nil
+}
+
+func RevokeAPIKey(ctx context.Context, id string) error {
+	key, err := FindAPIKeyById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if key.IsRevoked() {
+		return nil
+	}
+	return revokeAPIKey(ctx, key)
+}
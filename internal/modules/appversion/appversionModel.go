@@ -0,0 +1,23 @@
+package appversion
+
+import (
+	"github.com/addixit1/fiber-boilerplate/internal/config"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/reqctx"
+	"github.com/kamva/mgm/v3"
+)
+
+// AppVersion is the version policy of a mobile platform.
+// Clients below MinVersion must upgrade; clients below LatestVersion should.
+type AppVersion struct {
+	// MGM's DefaultModel includes: ID, CreatedAt, UpdatedAt
+	mgm.DefaultModel `bson:",inline"`
+	Platform         reqctx.Platform `bson:"platform" json:"platform"`
+	MinVersion       string          `bson:"min_version" json:"min_version"`
+	LatestVersion    string          `bson:"latest_version" json:"latest_version"`
+	UpdateURL        string          `bson:"update_url" json:"update_url"`
+}
+
+// CollectionName returns the MongoDB collection name for AppVersion model
+func (AppVersion) CollectionName() string {
+	return config.APP_VERSIONS_COLLECTION
+}
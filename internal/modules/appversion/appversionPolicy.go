@@ -0,0 +1,161 @@
+package appversion
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/addixit1/fiber-boilerplate/internal/config"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/redis"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/reqctx"
+	"github.com/addixit1/fiber-boilerplate/internal/querybuilder"
+	"github.com/addixit1/fiber-boilerplate/internal/utils/errortracker"
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Status is the outcome of checking a client version against its policy
+type Status int
+
+const (
+	UpToDate Status = iota
+	UpdateAvailable
+	UpdateRequired
+)
+
+// ErrInvalidVersion is returned for versions that are not dotted numbers
+var ErrInvalidVersion = errors.New("invalid version")
+
+var repo = querybuilder.NewBaseRepository()
+
+// cacheKey returns the Redis key caching a platform's policy
+func cacheKey(platform reqctx.Platform) string {
+	return "appversion:policy:" + strconv.Itoa(int(platform))
+}
+
+// Policy returns the version policy of platform, or nil when none is set.
+// Policies are cached in Redis for appVersion.cacheTTL; Mongo is read
+// directly when Redis is unavailable.
+func Policy(ctx context.Context, platform reqctx.Platform) (*AppVersion, error) {
+	key := cacheKey(platform)
+	if cached, err := redis.Get(key); err == nil {
+		var policy *AppVersion
+		if err := json.Unmarshal([]byte(cached), &policy); err == nil {
+			return policy, nil
+		}
+	} else if !errors.Is(err, redis.Nil) {
+		errortracker.Track(errortracker.LayerService, "Failed to read cached app version policy", err)
+	}
+
+	policy := &AppVersion{}
+	err := repo.FindOne(ctx, policy, bson.M{"platform": platform}, nil)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		policy = nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	// A missing policy is cached as null so unconfigured platforms don't hit Mongo
+	if ttl := config.Current().AppVersion.CacheTTL; ttl > 0 {
+		data, _ := json.Marshal(policy)
+		if err := redis.Set(key, string(data), ttl); err != nil {
+			errortracker.Track(errortracker.LayerService, "Failed to cache app version policy", err)
+		}
+	}
+	return policy, nil
+}
+
+// Invalidate drops the cached policy of platform
+func Invalidate(platform reqctx.Platform) {
+	if err := redis.Del(cacheKey(platform)); err != nil {
+		errortracker.Track(errortracker.LayerService, "Failed to invalidate app version policy", err)
+	}
+}
+
+// Check compares a client version with the policy of its platform.
+// The policy is nil when the platform has none.
+func Check(ctx context.Context, platform reqctx.Platform, version string) (Status, *AppVersion, error) {
+	policy, err := Policy(ctx, platform)
+	if err != nil || policy == nil {
+		return UpToDate, policy, err
+	}
+
+	if cmp, err := CompareVersions(version, policy.MinVersion); err != nil {
+		return UpToDate, policy, err
+	} else if cmp < 0 {
+		return UpdateRequired, policy, nil
+	}
+	if cmp, err := CompareVersions(version, policy.LatestVersion); err != nil {
+		return UpToDate, policy, err
+	} else if cmp < 0 {
+		return UpdateAvailable, policy, nil
+	}
+	return UpToDate, policy, nil
+}
+
+// ParseVersion splits a version such as "2.4.1" or "v2.4" into its numeric
+// parts. Pre-release and build suffixes ("-beta", "+42") are ignored.
+func ParseVersion(version string) ([]int, error) {
+	v := strings.TrimPrefix(strings.TrimSpace(version), "v")
+	if i := strings.IndexAny(v, "-+"); i >= 0 {
+		v = v[:i]
+	}
+	if v == "" {
+		return nil, fmt.Errorf("%w %q", ErrInvalidVersion, version)
+	}
+
+	parts := strings.Split(v, ".")
+	out := make([]int, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%w %q", ErrInvalidVersion, version)
+		}
+		out[i] = n
+	}
+	return out, nil
+}
+
+// CompareVersions returns -1, 0 or 1 as a is older than, equal to or newer
+// than b. Missing parts count as zero, so "2.1" equals "2.1.0".
+func CompareVersions(a, b string) (int, error) {
+	pa, err := ParseVersion(a)
+	if err != nil {
+		return 0, err
+	}
+	pb, err := ParseVersion(b)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1, nil
+			}
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+// EnsureIndexes creates the unique platform index
+func EnsureIndexes(ctx context.Context) error {
+	_, err := mgm.Coll(&AppVersion{}).Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "platform", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
@@ -0,0 +1,57 @@
+package appversion
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "1.0.0", b: "1.0.0", want: 0},
+		{a: "2.1", b: "2.1.0", want: 0},
+		{a: "v2.1.0", b: "2.1", want: 0},
+		{a: "2.1.0-beta", b: "2.1.0+42", want: 0},
+		{a: "1.9.9", b: "2.0.0", want: -1},
+		{a: "2.10.0", b: "2.9.0", want: 1},
+		{a: "2.1", b: "2.1.1", want: -1},
+		{a: "3", b: "2.99.99", want: 1},
+		{a: " 1.2.3 ", b: "1.2.3", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			got, err := CompareVersions(tt.a, tt.b)
+			if err != nil {
+				t.Fatalf("CompareVersions() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareVersionsInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+	}{
+		{name: "empty", a: "", b: "1.0"},
+		{name: "prefix only", a: "v", b: "1.0"},
+		{name: "letters", a: "1.x", b: "1.0"},
+		{name: "empty part", a: "1..2", b: "1.0"},
+		{name: "trailing dot", a: "1.0", b: "1."},
+		{name: "suffix only", a: "1.0", b: "-beta"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := CompareVersions(tt.a, tt.b); !errors.Is(err, ErrInvalidVersion) {
+				t.Errorf("CompareVersions(%q, %q) error = %v, want ErrInvalidVersion", tt.a, tt.b, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package appversion
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// Locales returns the module's locale bundles, registered by modules.RegisterLocales
+func Locales() fs.FS {
+	bundles, _ := fs.Sub(localeFiles, "locales")
+	return bundles
+}
@@ -0,0 +1,129 @@
+package appversionv1
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/addixit1/fiber-boilerplate/internal/config"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/locale"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/reqctx"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/timezone"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/validator"
+	"github.com/addixit1/fiber-boilerplate/internal/modules/appversion"
+	"github.com/addixit1/fiber-boilerplate/internal/utils/errortracker"
+	"github.com/gofiber/fiber/v2"
+)
+
+// Check godoc
+// @Summary Check the app version
+// @Description Compare the caller's appversion header with the minimum and latest versions of its platform
+// @Tags App Versions
+// @Accept json
+// @Produce json
+// @Param platform header string false "Device OS: 1-Android, 2-iOS, 3-WEB" Enums(1,2,3) default(1)
+// @Param appversion header string false "App version" default(1.0.0)
+// @Param accept-language header string false "Language: en, hi" Enums(en,hi) default(en)
+// @Success 200 {object} config.APIResponse
+// @Failure 426 {object} config.APIResponse
+// @Router /app-version [get]
+func Check(c *fiber.Ctx) error {
+	rc := reqctx.From(c)
+
+	status, policy, err := appversion.Check(c.UserContext(), rc.Platform, rc.AppVersion)
+	if errors.Is(err, appversion.ErrInvalidVersion) {
+		return c.Status(400).JSON(config.ErrorWithParams("INVALID_APP_VERSION", locale.Params{"version": rc.AppVersion}, rc.Lang))
+	}
+	if err != nil {
+		errortracker.Track(errortracker.LayerController, "Failed to check app version", err)
+		return c.Status(500).JSON(config.InternalServerError(rc.Lang))
+	}
+
+	result := &VersionCheckDTO{Platform: rc.Platform.String(), CurrentVersion: rc.AppVersion}
+	if policy != nil {
+		result.MinVersion = policy.MinVersion
+		result.LatestVersion = policy.LatestVersion
+		result.UpdateURL = policy.UpdateURL
+	}
+
+	switch status {
+	case appversion.UpdateRequired:
+		return timezone.JSON(c.Status(426), config.ForceUpdate(result, rc.Lang))
+	case appversion.UpdateAvailable:
+		return timezone.JSON(c.Status(200), config.UpdateAvailable(result, rc.Lang))
+	default:
+		return timezone.JSON(c.Status(200), config.AppUpToDate(result, rc.Lang))
+	}
+}
+
+// List godoc
+// @Summary List app version policies
+// @Description Get the minimum and latest app versions of every platform
+// @Tags App Versions
+// @Accept json
+// @Produce json
+// @Param accept-language header string false "Language: en, hi" Enums(en,hi) default(en)
+// @Security basicAuth
+// @Success 200 {object} config.APIResponse
+// @Failure 401 {object} config.APIResponse
+// @Failure 403 {object} config.APIResponse
+// @Router /app-versions [get]
+func List(c *fiber.Ctx) error {
+	lang := reqctx.Lang(c)
+
+	policies, err := ListAppVersions()
+	if err != nil {
+		errortracker.Track(errortracker.LayerController, "Failed to list app versions", err)
+		return c.Status(500).JSON(config.InternalServerError(lang))
+	}
+
+	return timezone.JSON(c.Status(200), config.List(policies, lang))
+}
+
+// Set godoc
+// @Summary Set an app version policy
+// @Description Set the minimum and latest app versions of a platform. Clients below the minimum get 426 FORCE_UPDATE.
+// @Tags App Versions
+// @Accept json
+// @Produce json
+// @Param platform path integer true "Platform: 1-Android, 2-iOS" Enums(1,2)
+// @Param body body SetAppVersionDTO true "Version policy"
+// @Param accept-language header string false "Language: en, hi" Enums(en,hi) default(en)
+// @Security basicAuth
+// @Success 200 {object} config.APIResponse
+// @Failure 400 {object} config.APIResponse
+// @Failure 401 {object} config.APIResponse
+// @Router /app-versions/{platform} [put]
+func Set(c *fiber.Ctx) error {
+	lang := reqctx.Lang(c)
+
+	number, err := strconv.Atoi(c.Params("platform"))
+	platform := reqctx.Platform(number)
+	if err != nil || (platform != reqctx.PlatformAndroid && platform != reqctx.PlatformIOS) {
+		return c.Status(400).JSON(config.Error("INVALID_APP_PLATFORM", lang))
+	}
+
+	var policyData SetAppVersionDTO
+	if err := c.BodyParser(&policyData); err != nil {
+		errortracker.Track(errortracker.LayerController, "Failed to parse request body", err)
+		return c.Status(400).JSON(config.Error("INVALID_REQUEST_BODY", lang))
+	}
+	if errs := validator.Struct(&policyData); len(errs) > 0 {
+		return c.Status(400).JSON(config.ValidationError(errs, lang))
+	}
+	for _, version := range []string{policyData.MinVersion, policyData.LatestVersion} {
+		if _, err := appversion.ParseVersion(version); err != nil {
+			return c.Status(400).JSON(config.ErrorWithParams("INVALID_APP_VERSION", locale.Params{"version": version}, lang))
+		}
+	}
+
+	policy, err := SetAppVersion(c.UserContext(), platform, &policyData)
+	if errors.Is(err, ErrMinAfterLatest) {
+		return c.Status(400).JSON(config.Error("MIN_VERSION_AFTER_LATEST", lang))
+	}
+	if err != nil {
+		errortracker.Track(errortracker.LayerController, "Failed to set app version", err)
+		return c.Status(500).JSON(config.InternalServerError(lang))
+	}
+
+	return timezone.JSON(c.Status(200), config.AppVersionUpdated(policy, lang))
+}
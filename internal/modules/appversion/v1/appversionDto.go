@@ -0,0 +1,17 @@
+package appversionv1
+
+// SetAppVersionDTO for setting a platform's version policy
+type SetAppVersionDTO struct {
+	MinVersion    string `json:"min_version" validate:"required" example:"2.0.0"`
+	LatestVersion string `json:"latest_version" validate:"required" example:"2.3.1"`
+	UpdateURL     string `json:"update_url" example:"https://play.google.com/store/apps/details?id=com.example.app"`
+}
+
+// VersionCheckDTO describes how the caller's app version compares with its platform policy
+type VersionCheckDTO struct {
+	Platform       string `json:"platform" example:"android"`
+	CurrentVersion string `json:"current_version" example:"2.1.0"`
+	MinVersion     string `json:"min_version,omitempty" example:"2.0.0"`
+	LatestVersion  string `json:"latest_version,omitempty" example:"2.3.1"`
+	UpdateURL      string `json:"update_url,omitempty"`
+}
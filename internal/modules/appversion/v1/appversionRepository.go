@@ -0,0 +1,44 @@
+package appversionv1
+
+import (
+	"context"
+
+	"github.com/addixit1/fiber-boilerplate/internal/lib/reqctx"
+	"github.com/addixit1/fiber-boilerplate/internal/modules/appversion"
+	"github.com/addixit1/fiber-boilerplate/internal/querybuilder"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var repo = querybuilder.NewBaseRepository()
+
+// FindAppVersions retrieves every platform policy
+func FindAppVersions() ([]appversion.AppVersion, error) {
+	ctx := context.Background()
+	policies := []appversion.AppVersion{}
+
+	opts := &querybuilder.FindOptions{Sort: bson.M{"platform": 1}}
+	if err := repo.Find(ctx, &appversion.AppVersion{}, &policies, bson.M{}, opts); err != nil {
+		return nil, err
+	}
+
+	return policies, nil
+}
+
+// FindAppVersionByPlatform retrieves the policy of a platform
+func FindAppVersionByPlatform(ctx context.Context, platform reqctx.Platform) (*appversion.AppVersion, error) {
+	found := &appversion.AppVersion{}
+	if err := repo.FindOne(ctx, found, bson.M{"platform": platform}, nil); err != nil {
+		return nil, err
+	}
+	return found, nil
+}
+
+// saveAppVersion creates a new policy
+func saveAppVersion(ctx context.Context, policy *appversion.AppVersion) error {
+	return repo.Save(ctx, policy)
+}
+
+// updateAppVersion replaces a stored policy
+func updateAppVersion(ctx context.Context, policy *appversion.AppVersion) error {
+	return repo.UpdateById(ctx, policy)
+}
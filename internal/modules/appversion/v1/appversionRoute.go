@@ -0,0 +1,14 @@
+package appversionv1
+
+import (
+	"github.com/addixit1/fiber-boilerplate/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+func Routes(r fiber.Router) {
+	admin := middleware.BasicAuth("appversions:admin")
+
+	r.Get("/app-version", Check)
+	r.Get("/app-versions", admin, List)
+	r.Put("/app-versions/:platform", admin, Set)
+}
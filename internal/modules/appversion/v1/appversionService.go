@@ -0,0 +1,50 @@
+package appversionv1
+
+import (
+	"context"
+	"errors"
+
+	"github.com/addixit1/fiber-boilerplate/internal/lib/reqctx"
+	"github.com/addixit1/fiber-boilerplate/internal/modules/appversion"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErrMinAfterLatest is returned when the minimum version is newer than the latest
+var ErrMinAfterLatest = errors.New("min_version is newer than latest_version")
+
+func ListAppVersions() ([]appversion.AppVersion, error) {
+	return FindAppVersions()
+}
+
+// SetAppVersion creates or replaces the policy of a platform and drops its cached copy
+func SetAppVersion(ctx context.Context, platform reqctx.Platform, data *SetAppVersionDTO) (*appversion.AppVersion, error) {
+	cmp, err := appversion.CompareVersions(data.MinVersion, data.LatestVersion)
+	if err != nil {
+		return nil, err
+	}
+	if cmp > 0 {
+		return nil, ErrMinAfterLatest
+	}
+
+	policy, err := FindAppVersionByPlatform(ctx, platform)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		policy = &appversion.AppVersion{Platform: platform}
+	} else if err != nil {
+		return nil, err
+	}
+
+	policy.MinVersion = data.MinVersion
+	policy.LatestVersion = data.LatestVersion
+	policy.UpdateURL = data.UpdateURL
+	if policy.ID.IsZero() {
+		err = saveAppVersion(ctx, policy)
+	} else {
+		err = updateAppVersion(ctx, policy)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	appversion.Invalidate(platform)
+	return policy, nil
+}
@@ -0,0 +1,135 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"time"
+
+	"github.com/addixit1/fiber-boilerplate/internal/config"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/reqctx"
+	"github.com/addixit1/fiber-boilerplate/internal/querybuilder"
+	"github.com/addixit1/fiber-boilerplate/internal/utils/errortracker"
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// redacted replaces the values of fields listed in AUDIT_REDACT_FIELDS
+const redacted = "[REDACTED]"
+
+// ignoredFields change on every write and are left out of diffs
+var ignoredFields = map[string]bool{"_id": true, "updated_at": true, "version": true}
+
+// Register starts auditing the collections in AUDIT_COLLECTIONS; changing
+// the list needs a restart. Every audited write costs two extra reads, of
+// the document before and after it, plus the entry insert, all made
+// synchronously on the writer's context: the request waits for them, and
+// inside a transaction they are part of it.
+func Register() {
+	querybuilder.OnChange(record, config.Config.Audit.Collections...)
+}
+
+// record stores the entry for one change. Entries are inserted directly,
+// not through BaseRepository, so they are never audited themselves.
+func record(ctx context.Context, change querybuilder.Change) {
+	cfg := config.Current().Audit
+	if !cfg.Enabled || change.Collection == config.AUDIT_LOGS_COLLECTION {
+		return
+	}
+
+	changes := Diff(change.Before, change.After, cfg.Redact)
+	if len(changes) == 0 {
+		return
+	}
+
+	entry := &Entry{
+		Collection: change.Collection,
+		DocumentID: change.DocumentID,
+		Action:     string(change.Action),
+		Changes:    changes,
+	}
+	if rc, ok := reqctx.FromContext(ctx); ok {
+		entry.RequestID = rc.RequestID
+		if rc.Principal != nil {
+			entry.Actor = &Actor{Kind: rc.Principal.Kind, ID: rc.Principal.ID, Name: rc.Principal.Name}
+		}
+	}
+
+	// The write already happened, so record it even if the request is cancelled
+	if err := mgm.Coll(entry).CreateWithCtx(context.WithoutCancel(ctx), entry); err != nil {
+		errortracker.Track(errortracker.LayerRepository, "Failed to write audit entry", err)
+	}
+}
+
+// Diff compares the top-level fields of two versions of a document.
+// Values of fields named in redact are replaced by "[REDACTED]".
+func Diff(before, after bson.M, redact []string) map[string]FieldChange {
+	hidden := make(map[string]bool, len(redact))
+	for _, field := range redact {
+		hidden[field] = true
+	}
+
+	changes := map[string]FieldChange{}
+	compare := func(field string) {
+		if ignoredFields[field] {
+			return
+		}
+		if _, seen := changes[field]; seen {
+			return
+		}
+
+		from, to := before[field], after[field]
+		if reflect.DeepEqual(from, to) {
+			return
+		}
+		if hidden[field] {
+			from, to = hide(from), hide(to)
+		}
+		changes[field] = FieldChange{From: from, To: to}
+	}
+
+	for field := range before {
+		compare(field)
+	}
+	for field := range after {
+		compare(field)
+	}
+	return changes
+}
+
+func hide(value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	return redacted
+}
+
+// EnsureIndexes creates the history lookup index and the TTL index that
+// expires entries after AUDIT_RETENTION, updating the TTL when it changed
+func EnsureIndexes(ctx context.Context) error {
+	indexes := mgm.Coll(&Entry{}).Indexes()
+	_, err := indexes.CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "collection", Value: 1}, {Key: "document_id", Value: 1}, {Key: "created_at", Value: -1}},
+	})
+	if err != nil {
+		return err
+	}
+
+	ttl := int32(config.Config.Audit.Retention / time.Second)
+	_, err = indexes.CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "created_at", Value: 1}},
+		Options: options.Index().SetName("audit_logs_ttl").SetExpireAfterSeconds(ttl),
+	})
+
+	// IndexOptionsConflict: the index exists with another retention
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && cmdErr.Code == 85 {
+		return mgm.Coll(&Entry{}).Database().RunCommand(ctx, bson.D{
+			{Key: "collMod", Value: config.AUDIT_LOGS_COLLECTION},
+			{Key: "index", Value: bson.D{{Key: "name", Value: "audit_logs_ttl"}, {Key: "expireAfterSeconds", Value: ttl}}},
+		}).Err()
+	}
+	return err
+}
@@ -0,0 +1,84 @@
+package audit
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name          string
+		before, after bson.M
+		redact        []string
+		want          map[string]FieldChange
+	}{
+		{
+			name:   "create",
+			before: nil,
+			after:  bson.M{"_id": 1, "name": "ada", "age": 36},
+			want: map[string]FieldChange{
+				"name": {From: nil, To: "ada"},
+				"age":  {From: nil, To: 36},
+			},
+		},
+		{
+			name:   "delete",
+			before: bson.M{"_id": 1, "name": "ada"},
+			after:  nil,
+			want:   map[string]FieldChange{"name": {From: "ada", To: nil}},
+		},
+		{
+			name:   "changed, added and removed fields",
+			before: bson.M{"name": "ada", "age": 36, "city": "London"},
+			after:  bson.M{"name": "ada", "age": 37, "email": "ada@example.com"},
+			want: map[string]FieldChange{
+				"age":   {From: 36, To: 37},
+				"city":  {From: "London", To: nil},
+				"email": {From: nil, To: "ada@example.com"},
+			},
+		},
+		{
+			name:   "nested values are compared deeply",
+			before: bson.M{"address": bson.M{"city": "London"}, "tags": bson.A{"a"}},
+			after:  bson.M{"address": bson.M{"city": "London"}, "tags": bson.A{"a", "b"}},
+			want:   map[string]FieldChange{"tags": {From: bson.A{"a"}, To: bson.A{"a", "b"}}},
+		},
+		{
+			name:   "bookkeeping fields are ignored",
+			before: bson.M{"_id": 1, "updated_at": 1, "version": 1},
+			after:  bson.M{"_id": 2, "updated_at": 2, "version": 2},
+			want:   map[string]FieldChange{},
+		},
+		{
+			name:   "redacted fields hide their values",
+			before: bson.M{"password": "old"},
+			after:  bson.M{"password": "new"},
+			redact: []string{"password"},
+			want:   map[string]FieldChange{"password": {From: redacted, To: redacted}},
+		},
+		{
+			name:   "redaction keeps a missing value nil",
+			before: nil,
+			after:  bson.M{"password": "new"},
+			redact: []string{"password"},
+			want:   map[string]FieldChange{"password": {From: nil, To: redacted}},
+		},
+		{
+			name:   "unchanged redacted fields are left out",
+			before: bson.M{"password": "same"},
+			after:  bson.M{"password": "same"},
+			redact: []string{"password"},
+			want:   map[string]FieldChange{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Diff(tt.before, tt.after, tt.redact); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package audit
+
+import (
+	"github.com/addixit1/fiber-boilerplate/internal/config"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/reqctx"
+	"github.com/kamva/mgm/v3"
+)
+
+// Entry records one change to one document. Entries expire after the
+// audit retention period.
+type Entry struct {
+	// MGM's DefaultModel includes: ID, CreatedAt, UpdatedAt
+	mgm.DefaultModel `bson:",inline"`
+	Collection       string                 `bson:"collection" json:"collection"`
+	DocumentID       interface{}            `bson:"document_id" json:"document_id"`
+	Action           string                 `bson:"action" json:"action"`
+	Actor            *Actor                 `bson:"actor,omitempty" json:"actor,omitempty"`
+	RequestID        string                 `bson:"request_id,omitempty" json:"request_id,omitempty"`
+	Changes          map[string]FieldChange `bson:"changes" json:"changes"`
+}
+
+// Actor is the principal that made a change
+type Actor struct {
+	Kind reqctx.PrincipalKind `bson:"kind" json:"kind"`
+	ID   string               `bson:"id" json:"id"`
+	Name string               `bson:"name,omitempty" json:"name,omitempty"`
+}
+
+// FieldChange holds the old and new value of a top-level field; From is
+// nil for added fields and To is nil for removed ones
+type FieldChange struct {
+	From interface{} `bson:"from" json:"from"`
+	To   interface{} `bson:"to" json:"to"`
+}
+
+// CollectionName returns the MongoDB collection name for Entry model
+func (Entry) CollectionName() string {
+	return config.AUDIT_LOGS_COLLECTION
+}
@@ -0,0 +1,37 @@
+package auditv1
+
+import (
+	"github.com/addixit1/fiber-boilerplate/internal/config"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/reqctx"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/timezone"
+	"github.com/addixit1/fiber-boilerplate/internal/utils/errortracker"
+	"github.com/gofiber/fiber/v2"
+)
+
+// History godoc
+// @Summary Document history
+// @Description Get the audit trail of a document, newest change first
+// @Tags Audit
+// @Accept json
+// @Produce json
+// @Param collection path string true "Collection name" example(users)
+// @Param id path string true "Document ID"
+// @Param page query integer false "Page number" default(1)
+// @Param limit query integer false "Page size" default(10)
+// @Param accept-language header string false "Language: en, hi" Enums(en,hi) default(en)
+// @Security basicAuth
+// @Success 200 {object} config.ListResponse
+// @Failure 401 {object} config.APIResponse
+// @Failure 403 {object} config.APIResponse
+// @Router /audit/{collection}/{id} [get]
+func History(c *fiber.Ctx) error {
+	lang := reqctx.Lang(c)
+
+	page, err := DocumentHistory(c.UserContext(), c.Params("collection"), c.Params("id"), c.QueryInt("page"), c.QueryInt("limit"))
+	if err != nil {
+		errortracker.Track(errortracker.LayerController, "Failed to load audit history", err)
+		return c.Status(500).JSON(config.InternalServerError(lang))
+	}
+
+	return timezone.JSON(c.Status(200), config.ListWithPagination(page.Items, config.NewPagination(page.Page, page.Limit, page.Total, page.HasNext), lang))
+}
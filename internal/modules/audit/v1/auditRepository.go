@@ -0,0 +1,23 @@
+package auditv1
+
+import (
+	"context"
+
+	"github.com/addixit1/fiber-boilerplate/internal/modules/audit"
+	"github.com/addixit1/fiber-boilerplate/internal/querybuilder"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var repo = querybuilder.NewRepository[*audit.Entry]()
+
+// FindHistory retrieves one page of the entries of a document, newest first
+func FindHistory(ctx context.Context, collection string, documentID interface{}, page, limit int) (*querybuilder.Page[*audit.Entry], error) {
+	filter := bson.D{{Key: "collection", Value: collection}, {Key: "document_id", Value: documentID}}
+	opts := querybuilder.PaginateOptions{
+		Page:  page,
+		Limit: limit,
+		Sort:  bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+	}
+
+	return repo.Paginate(ctx, filter, opts)
+}
@@ -0,0 +1,10 @@
+package auditv1
+
+import (
+	"github.com/addixit1/fiber-boilerplate/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+func Routes(r fiber.Router) {
+	r.Get("/audit/:collection/:id", middleware.BasicAuth("audit:read"), History)
+}
@@ -0,0 +1,20 @@
+package auditv1
+
+import (
+	"context"
+
+	"github.com/addixit1/fiber-boilerplate/internal/modules/audit"
+	"github.com/addixit1/fiber-boilerplate/internal/querybuilder"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DocumentHistory returns the audit entries of a document. IDs that look
+// like ObjectIDs are matched as ObjectIDs, others as strings.
+func DocumentHistory(ctx context.Context, collection, id string, page, limit int) (*querybuilder.Page[*audit.Entry], error) {
+	var documentID interface{} = id
+	if objectID, err := primitive.ObjectIDFromHex(id); err == nil {
+		documentID = objectID
+	}
+
+	return FindHistory(ctx, collection, documentID, page, limit)
+}
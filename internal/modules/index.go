@@ -0,0 +1,15 @@
+// Package modules lists what the server and its tools need from every module
+package modules
+
+import (
+	"github.com/addixit1/fiber-boilerplate/internal/lib/locale"
+	"github.com/addixit1/fiber-boilerplate/internal/modules/apikey"
+	"github.com/addixit1/fiber-boilerplate/internal/modules/appversion"
+)
+
+// RegisterLocales adds the locale bundles of every module to the catalogue.
+// Call it once, before locale.Load; add new modules with bundles here.
+func RegisterLocales() {
+	locale.Register("apikey", apikey.Locales())
+	locale.Register("appversion", appversion.Locales())
+}
@@ -0,0 +1,66 @@
+package lockoutv1
+
+import (
+	"errors"
+
+	"github.com/addixit1/fiber-boilerplate/internal/config"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/bruteforce"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/reqctx"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/timezone"
+	"github.com/addixit1/fiber-boilerplate/internal/utils/errortracker"
+	"github.com/gofiber/fiber/v2"
+)
+
+// List godoc
+// @Summary List lockouts
+// @Description Get every account and IP currently locked out after failed logins
+// @Tags Lockouts
+// @Accept json
+// @Produce json
+// @Param accept-language header string false "Language: en, hi" Enums(en,hi) default(en)
+// @Security basicAuth
+// @Success 200 {object} config.APIResponse
+// @Failure 401 {object} config.APIResponse
+// @Failure 403 {object} config.APIResponse
+// @Router /lockouts [get]
+func List(c *fiber.Ctx) error {
+	lang := reqctx.Lang(c)
+
+	lockouts, err := bruteforce.List(c.UserContext())
+	if err != nil {
+		errortracker.Track(errortracker.LayerController, "Failed to list lockouts", err)
+		return c.Status(500).JSON(config.InternalServerError(lang))
+	}
+
+	return timezone.JSON(c.Status(200), config.List(lockouts, lang))
+}
+
+// Clear godoc
+// @Summary Clear a lockout
+// @Description Unlock an account or IP and reset its failed attempts
+// @Tags Lockouts
+// @Accept json
+// @Produce json
+// @Param scope path string true "Login flow, e.g. basicauth"
+// @Param kind path string true "account or ip" Enums(account,ip)
+// @Param subject path string true "Username@IP, as listed, or IP address"
+// @Param accept-language header string false "Language: en, hi" Enums(en,hi) default(en)
+// @Security basicAuth
+// @Success 200 {object} config.APIResponse
+// @Failure 400 {object} config.APIResponse
+// @Failure 401 {object} config.APIResponse
+// @Router /lockouts/{scope}/{kind}/{subject} [delete]
+func Clear(c *fiber.Ctx) error {
+	lang := reqctx.Lang(c)
+
+	err := bruteforce.Clear(c.Params("scope"), bruteforce.Kind(c.Params("kind")), c.Params("subject"))
+	if errors.Is(err, bruteforce.ErrInvalidKind) {
+		return c.Status(400).JSON(config.Error("INVALID_LOCKOUT_KIND", lang))
+	}
+	if err != nil {
+		errortracker.Track(errortracker.LayerController, "Failed to clear lockout", err)
+		return c.Status(500).JSON(config.InternalServerError(lang))
+	}
+
+	return c.Status(200).JSON(config.LockoutCleared(lang))
+}
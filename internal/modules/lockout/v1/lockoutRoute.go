@@ -0,0 +1,13 @@
+package lockoutv1
+
+import (
+	"github.com/addixit1/fiber-boilerplate/internal/middleware"
+	"github.com/gofiber/fiber/v2"
+)
+
+func Routes(r fiber.Router) {
+	admin := middleware.BasicAuth("security:admin")
+
+	r.Get("/lockouts", admin, List)
+	r.Delete("/lockouts/:scope/:kind/:subject", admin, Clear)
+}
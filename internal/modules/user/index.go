@@ -1,2 +1,33 @@
 package user
 
+import (
+	"context"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// EnsureIndexes creates the text index used to search users by name and
+// email, the created_at index backing cursor pagination and the deleted_at
+// index used to purge soft-deleted users
+func EnsureIndexes(ctx context.Context) error {
+	_, err := mgm.Coll(&User{}).Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{{Key: "name", Value: "text"}, {Key: "email", Value: "text"}},
+			Options: options.Index().
+				SetName("users_text").
+				SetWeights(bson.D{{Key: "name", Value: 10}, {Key: "email", Value: 1}}),
+		},
+		{
+			Keys:    bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+			Options: options.Index().SetName("users_created_at"),
+		},
+		{
+			Keys:    bson.D{{Key: "deleted_at", Value: 1}},
+			Options: options.Index().SetName("users_deleted_at").SetSparse(true),
+		},
+	})
+	return err
+}
@@ -2,6 +2,7 @@ package user
 
 import (
 	"github.com/addixit1/fiber-boilerplate/internal/config"
+	"github.com/addixit1/fiber-boilerplate/internal/querybuilder"
 	"github.com/kamva/mgm/v3"
 )
 
@@ -9,8 +10,12 @@ import (
 type User struct {
 	// MGM's DefaultModel includes: ID, CreatedAt, UpdatedAt
 	mgm.DefaultModel `bson:",inline"`
-	Name             string `bson:"name" json:"name"`
-	Email            string `bson:"email" json:"email"`
+	// Deleted users are kept until the soft delete retention passes
+	querybuilder.SoftDeleteModel `bson:",inline"`
+	// Version guards updates against concurrent edits; it is the ETag
+	querybuilder.VersionedModel `bson:",inline"`
+	Name                        string `bson:"name" json:"name"`
+	Email                       string `bson:"email" json:"email"`
 }
 
 // CollectionName returns the MongoDB collection name for User model
@@ -1,51 +1,84 @@
 package userv1
 
 import (
+	"errors"
+
 	"github.com/addixit1/fiber-boilerplate/internal/config"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/etag"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/locale"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/reqctx"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/timezone"
+	"github.com/addixit1/fiber-boilerplate/internal/lib/validator"
 	"github.com/addixit1/fiber-boilerplate/internal/querybuilder"
 	"github.com/addixit1/fiber-boilerplate/internal/utils/errortracker"
 	"github.com/gofiber/fiber/v2"
 )
 
-// getLang gets language from context
-func getLang(c *fiber.Ctx) string {
-	lang, ok := c.Locals("lang").(string)
-	if !ok || lang == "" {
-		return "en" // Default to English
-	}
-	return lang
-}
-
 // ListUsers godoc
 // @Summary List users
 // @Description Get all users with search
 // @Tags Users
 // @Accept json
 // @Produce json
-// @Param search query string false "Search by name"
+// @Param search query string false "Search by name or email"
+// @Param filter[name][contains] query string false "Filter: filter[field][op]=value, op is eq, ne, gt, gte, lt, lte, in, nin, contains, prefix or exists"
+// @Param sort query string false "Sort fields, - for descending" default(-created_at)
+// @Param fields query string false "Fields to return" example(name,email)
+// @Param page query integer false "Page number; enables page/limit pagination"
+// @Param limit query integer false "Page size; without page enables cursor pagination" default(10)
+// @Param cursor query string false "nextCursor or prevCursor of a previous page"
 // @Param platform header string false "Device OS: 1-Android, 2-iOS, 3-WEB" Enums(1,2,3) default(1)
 // @Param timezone header string false "Time zone" default(Asia/Kolkata)
-// @Param offset header integer false "Time zone offset" default(0)
+// @Param offset header integer false "Time zone offset in minutes east of UTC, used when timezone is not a valid IANA name" default(0)
 // @Param accept-language header string false "Language: en, hi" Enums(en,hi) default(en)
 // @Param appversion header string false "App version" default(v1)
 // @Param routeversion header string false "Route version" default(v1)
-// @Security BearerAuth
+// @Security basicAuth
+// @Security APIKeyAuth
 // @Success 200 {object} config.APIResponse
+// @Failure 400 {object} config.APIResponse
 // @Failure 401 {object} config.APIResponse
 // @Router /users [get]
 func List(c *fiber.Ctx) error {
-	lang := getLang(c)
+	lang := reqctx.Lang(c)
 
-	filter := querybuilder.New().
-		Regex("name", c.Query("search")).
-		Build()
+	query, err := querybuilder.ParseQuery(string(c.Request().URI().QueryString()), listQuery)
+	var queryErr *querybuilder.QueryError
+	if errors.As(err, &queryErr) {
+		return c.Status(400).JSON(config.InvalidQuery(queryErr.Param, queryErr.Reason, queryErr.Value, lang))
+	}
 
-	users, err := ListUsers(filter)
+	if c.Query("page") != "" {
+		page, err := ListUsersByPage(c.UserContext(), c.Query("search"), query, c.QueryInt("page"), c.QueryInt("limit"))
+		if err != nil {
+			return c.Status(500).JSON(config.InternalServerError(lang))
+		}
+		return timezone.JSON(c.Status(200), config.ListWithPagination(page.Items, config.NewPagination(page.Page, page.Limit, page.Total, page.HasNext), lang))
+	}
+
+	if cursor := c.Query("cursor"); cursor != "" || c.Query("limit") != "" {
+		users, page, err := ListUsersByCursor(c.UserContext(), c.Query("search"), query, cursor, c.QueryInt("limit"))
+		if errors.Is(err, querybuilder.ErrInvalidCursor) {
+			return c.Status(400).JSON(config.InvalidQuery("cursor", "invalid_value", cursor, lang))
+		}
+		if err != nil {
+			return c.Status(500).JSON(config.InternalServerError(lang))
+		}
+		return timezone.JSON(c.Status(200), config.ListWithCursor(users, config.CursorPagination{
+			NextCursor: page.NextCursor,
+			PrevCursor: page.PrevCursor,
+			HasNext:    page.HasNext,
+			HasPrev:    page.HasPrev,
+			Limit:      page.Limit,
+		}, lang))
+	}
+
+	users, err := ListUsers(c.UserContext(), c.Query("search"), query)
 	if err != nil {
 		return c.Status(500).JSON(config.InternalServerError(lang))
 	}
 
-	return c.Status(200).JSON(config.List(users, lang))
+	return timezone.JSON(c.Status(200), config.List(users, lang))
 }
 
 // CreateUser godoc
@@ -57,7 +90,7 @@ func List(c *fiber.Ctx) error {
 // @Param body body CreateUserDTO true "Create user"
 // @Param platform header string false "Device OS: 1-Android, 2-iOS, 3-WEB" Enums(1,2,3) default(1)
 // @Param timezone header string false "Time zone" default(Asia/Kolkata)
-// @Param offset header integer false "Time zone offset" default(0)
+// @Param offset header integer false "Time zone offset in minutes east of UTC, used when timezone is not a valid IANA name" default(0)
 // @Param accept-language header string false "Language: en, hi" Enums(en,hi) default(en)
 // @Param appversion header string false "App version" default(v1)
 // @Param routeversion header string false "Route version" default(v1)
@@ -67,18 +100,186 @@ func List(c *fiber.Ctx) error {
 // @Failure 401 {object} config.APIResponse
 // @Router /users [post]
 func Create(c *fiber.Ctx) error {
-	lang := getLang(c)
+	lang := reqctx.Lang(c)
 
 	var userData CreateUserDTO
 	if err := c.BodyParser(&userData); err != nil {
 		errortracker.Track(errortracker.LayerController, "Failed to parse request body", err)
-		return c.Status(400).JSON(config.Error("Invalid request body", lang))
+		return c.Status(400).JSON(config.Error("INVALID_REQUEST_BODY", lang))
+	}
+	if errs := validator.Struct(&userData); len(errs) > 0 {
+		return c.Status(400).JSON(config.ValidationError(errs, lang))
 	}
 
-	if err := CreateUser(&userData); err != nil {
+	err := CreateUser(c.UserContext(), &userData)
+	if errors.Is(err, ErrEmailTaken) {
+		return c.Status(400).JSON(config.EmailAlreadyExists(lang))
+	}
+	if err != nil {
 		errortracker.Track(errortracker.LayerController, "Failed to create user", err)
 		return c.Status(500).JSON(config.InternalServerError(lang))
 	}
 
 	return c.Status(201).JSON(config.Signup(userData, lang))
 }
+
+// GetUser godoc
+// @Summary Get a user
+// @Description Get a user by ID; the ETag header carries its version for If-Match
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param platform header string false "Device OS: 1-Android, 2-iOS, 3-WEB" Enums(1,2,3) default(1)
+// @Param timezone header string false "Time zone" default(Asia/Kolkata)
+// @Param offset header integer false "Time zone offset in minutes east of UTC, used when timezone is not a valid IANA name" default(0)
+// @Param accept-language header string false "Language: en, hi" Enums(en,hi) default(en)
+// @Param appversion header string false "App version" default(v1)
+// @Param routeversion header string false "Route version" default(v1)
+// @Security basicAuth
+// @Security APIKeyAuth
+// @Success 200 {object} config.APIResponse{data=UserResponseDTO}
+// @Header 200 {string} ETag "User version"
+// @Failure 401 {object} config.APIResponse
+// @Failure 404 {object} config.APIResponse
+// @Router /users/{id} [get]
+func Get(c *fiber.Ctx) error {
+	lang := reqctx.Lang(c)
+
+	found, err := FindUserById(c.Params("id"))
+	if querybuilder.IsNotFound(err) {
+		return c.Status(404).JSON(config.UserNotFound(lang))
+	}
+	if err != nil {
+		errortracker.Track(errortracker.LayerController, "Failed to get user", err)
+		return c.Status(500).JSON(config.InternalServerError(lang))
+	}
+
+	c.Set(fiber.HeaderETag, etag.Format(found.Version))
+	return timezone.JSON(c.Status(200), config.Details(found, lang))
+}
+
+// UpdateUser godoc
+// @Summary Update a user
+// @Description Update the given fields of a user. Send the ETag of the copy being edited as If-Match to get 409 instead of overwriting someone else's changes.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param body body UpdateUserDTO true "Fields to change"
+// @Param If-Match header string false "ETag from GET /users/{id}" example("3")
+// @Param platform header string false "Device OS: 1-Android, 2-iOS, 3-WEB" Enums(1,2,3) default(1)
+// @Param timezone header string false "Time zone" default(Asia/Kolkata)
+// @Param offset header integer false "Time zone offset in minutes east of UTC, used when timezone is not a valid IANA name" default(0)
+// @Param accept-language header string false "Language: en, hi" Enums(en,hi) default(en)
+// @Param appversion header string false "App version" default(v1)
+// @Param routeversion header string false "Route version" default(v1)
+// @Security basicAuth
+// @Success 200 {object} config.APIResponse{data=UserResponseDTO}
+// @Header 200 {string} ETag "New user version"
+// @Failure 400 {object} config.APIResponse
+// @Failure 401 {object} config.APIResponse
+// @Failure 404 {object} config.APIResponse
+// @Failure 409 {object} config.APIResponse
+// @Router /users/{id} [patch]
+func Update(c *fiber.Ctx) error {
+	lang := reqctx.Lang(c)
+
+	version, conditional, err := etag.ParseIfMatch(c.Get(fiber.HeaderIfMatch))
+	if err != nil {
+		return c.Status(400).JSON(config.ErrorWithParams("INVALID_HEADER", locale.Params{"header": fiber.HeaderIfMatch}, lang))
+	}
+
+	var userData UpdateUserDTO
+	if err := c.BodyParser(&userData); err != nil || (userData.Name == nil && userData.Email == nil) {
+		return c.Status(400).JSON(config.Error("INVALID_REQUEST_BODY", lang))
+	}
+	if errs := validator.Struct(&userData); len(errs) > 0 {
+		return c.Status(400).JSON(config.ValidationError(errs, lang))
+	}
+
+	var expected *int64
+	if conditional {
+		expected = &version
+	}
+	updated, err := EditUser(c.UserContext(), c.Params("id"), &userData, expected)
+	if querybuilder.IsConflict(err) {
+		return c.Status(409).JSON(config.VersionConflict(lang))
+	}
+	if querybuilder.IsNotFound(err) {
+		return c.Status(404).JSON(config.UserNotFound(lang))
+	}
+	if err != nil {
+		errortracker.Track(errortracker.LayerController, "Failed to update user", err)
+		return c.Status(500).JSON(config.InternalServerError(lang))
+	}
+
+	c.Set(fiber.HeaderETag, etag.Format(updated.Version))
+	return timezone.JSON(c.Status(200), config.UserUpdated(updated, lang))
+}
+
+// DeleteUser godoc
+// @Summary Delete a user
+// @Description Soft-delete a user; it can be restored until the retention period passes
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param platform header string false "Device OS: 1-Android, 2-iOS, 3-WEB" Enums(1,2,3) default(1)
+// @Param timezone header string false "Time zone" default(Asia/Kolkata)
+// @Param offset header integer false "Time zone offset in minutes east of UTC, used when timezone is not a valid IANA name" default(0)
+// @Param accept-language header string false "Language: en, hi" Enums(en,hi) default(en)
+// @Param appversion header string false "App version" default(v1)
+// @Param routeversion header string false "Route version" default(v1)
+// @Security basicAuth
+// @Success 200 {object} config.APIResponse
+// @Failure 401 {object} config.APIResponse
+// @Failure 404 {object} config.APIResponse
+// @Router /users/{id} [delete]
+func Delete(c *fiber.Ctx) error {
+	lang := reqctx.Lang(c)
+
+	err := DeleteUser(c.UserContext(), c.Params("id"))
+	if querybuilder.IsNotFound(err) {
+		return c.Status(404).JSON(config.UserNotFound(lang))
+	}
+	if err != nil {
+		errortracker.Track(errortracker.LayerController, "Failed to delete user", err)
+		return c.Status(500).JSON(config.InternalServerError(lang))
+	}
+
+	return c.Status(200).JSON(config.UserDeleted(lang))
+}
+
+// RestoreUser godoc
+// @Summary Restore a deleted user
+// @Description Restore a soft-deleted user that has not been purged yet
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param id path string true "User ID"
+// @Param platform header string false "Device OS: 1-Android, 2-iOS, 3-WEB" Enums(1,2,3) default(1)
+// @Param timezone header string false "Time zone" default(Asia/Kolkata)
+// @Param offset header integer false "Time zone offset in minutes east of UTC, used when timezone is not a valid IANA name" default(0)
+// @Param accept-language header string false "Language: en, hi" Enums(en,hi) default(en)
+// @Param appversion header string false "App version" default(v1)
+// @Param routeversion header string false "Route version" default(v1)
+// @Security basicAuth
+// @Success 200 {object} config.APIResponse
+// @Failure 401 {object} config.APIResponse
+// @Failure 404 {object} config.APIResponse
+// @Router /users/{id}/restore [post]
+func Restore(c *fiber.Ctx) error {
+	lang := reqctx.Lang(c)
+
+	err := RestoreUser(c.UserContext(), c.Params("id"))
+	if querybuilder.IsNotFound(err) {
+		return c.Status(404).JSON(config.UserNotFound(lang))
+	}
+	if err != nil {
+		errortracker.Track(errortracker.LayerController, "Failed to restore user", err)
+		return c.Status(500).JSON(config.InternalServerError(lang))
+	}
+
+	return c.Status(200).JSON(config.UserRestored(lang))
+}
@@ -1,6 +1,20 @@
 package userv1
 
-import "time"
+import (
+	"time"
+
+	"github.com/addixit1/fiber-boilerplate/internal/modules/user"
+	"github.com/addixit1/fiber-boilerplate/internal/querybuilder"
+)
+
+// listQuery whitelists the filter, sort and fields parameters of GET /users
+var listQuery = querybuilder.QuerySpec{
+	Model:       user.User{},
+	Filterable:  []string{"id", "name", "email", "created_at", "updated_at"},
+	Sortable:    []string{"name", "email", "created_at", "updated_at"},
+	Selectable:  []string{"name", "email", "created_at", "updated_at"},
+	DefaultSort: "-created_at",
+}
 
 // CreateUserDTO for creating a new user
 type CreateUserDTO struct {
@@ -8,6 +22,12 @@ type CreateUserDTO struct {
 	Email string `json:"email" validate:"required,email" example:"aman@gmail.com"`
 }
 
+// UpdateUserDTO for updating a user; omitted fields are left unchanged
+type UpdateUserDTO struct {
+	Name  *string `json:"name,omitempty" validate:"min=1" example:"Aman Dixit"`
+	Email *string `json:"email,omitempty" validate:"min=1,email" example:"aman@gmail.com"`
+}
+
 // UserResponseDTO for API responses (Swagger compatible)
 type UserResponseDTO struct {
 	ID        string    `json:"id" example:"507f1f77bcf86cd799439011"`
@@ -15,4 +35,5 @@ type UserResponseDTO struct {
 	Email     string    `json:"email" example:"aman@gmail.com"`
 	CreatedAt time.Time `json:"created_at" example:"2024-01-01T12:00:00Z"`
 	UpdatedAt time.Time `json:"updated_at" example:"2024-01-01T12:00:00Z"`
+	Version   int64     `json:"version" example:"3"`
 }
@@ -2,133 +2,125 @@ package userv1
 
 import (
 	"context"
+	"time"
 
 	"github.com/addixit1/fiber-boilerplate/internal/modules/user"
 	"github.com/addixit1/fiber-boilerplate/internal/querybuilder"
 	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
-var repo = querybuilder.NewBaseRepository()
+var repo = querybuilder.NewRepository[*user.User]()
 
-// FindUsers retrieves users based on filter using base repository
-func FindUsers(filter bson.M) ([]user.User, error) {
-	ctx := context.Background()
-	users := []user.User{}
-
-	// Using base repository's Find method
-	err := repo.Find(ctx, &user.User{}, &users, filter, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	return users, nil
+// FindUsers retrieves users based on filter
+func FindUsers(ctx context.Context, filter interface{}, opts *querybuilder.FindOptions) ([]*user.User, error) {
+	return repo.Find(ctx, filter, opts)
 }
 
-// FindUserById retrieves a user by ID
-func FindUserById(id string) (*user.User, error) {
-	ctx := context.Background()
+// SearchUsers retrieves users matching query with an Atlas Search index and
+// filter, best matches first unless opts sets a sort
+func SearchUsers(ctx context.Context, index, query string, filter interface{}, opts *querybuilder.FindOptions) ([]*user.User, error) {
+	users := []*user.User{}
 
-	// Convert string ID to ObjectID
-	objectID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		return nil, err
+	pipeline := mongo.Pipeline{
+		querybuilder.SearchStage(index, query, "name", "email"),
+		{{Key: "$match", Value: filter}},
 	}
-
-	foundUser := &user.User{}
-	err = repo.FindById(ctx, foundUser, objectID)
-	if err != nil {
+	if opts != nil && opts.Sort != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$sort", Value: opts.Sort}})
+	}
+	if opts != nil && opts.Projection != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$project", Value: opts.Projection}})
+	}
+	if err := repo.Base().Aggregate(ctx, &user.User{}, pipeline, &users); err != nil {
 		return nil, err
 	}
 
-	return foundUser, nil
+	return users, nil
 }
 
-// FindUserByEmail retrieves a user by email
-func FindUserByEmail(email string) (*user.User, error) {
-	ctx := context.Background()
-	foundUser := &user.User{}
+// FindUsersWithCursor retrieves one keyset-paginated page of users
+func FindUsersWithCursor(ctx context.Context, filter interface{}, opts querybuilder.CursorOptions) ([]*user.User, *querybuilder.CursorPage, error) {
+	return repo.FindWithCursor(ctx, filter, opts)
+}
 
-	filter := bson.M{"email": email}
-	err := repo.FindOne(ctx, foundUser, filter, nil)
-	if err != nil {
-		return nil, err
+// SearchUsersWithCursor is SearchUsers with keyset pagination
+func SearchUsersWithCursor(ctx context.Context, index, query string, filter interface{}, opts querybuilder.CursorOptions) ([]*user.User, *querybuilder.CursorPage, error) {
+	pipeline := mongo.Pipeline{
+		querybuilder.SearchStage(index, query, "name", "email"),
+		{{Key: "$match", Value: filter}},
 	}
+	return repo.AggregateWithCursor(ctx, pipeline, opts)
+}
 
-	return foundUser, nil
+// FindUserById retrieves a user by ID; a missing user is a *querybuilder.NotFoundError
+func FindUserById(id string) (*user.User, error) {
+	return repo.FindByID(context.Background(), id)
 }
 
-// saveUser creates a new user using base repository
-func saveUser(userData *CreateUserDTO) (*user.User, error) {
-	ctx := context.Background()
+// FindUserByEmail retrieves a user by email
+func FindUserByEmail(ctx context.Context, email string) (*user.User, error) {
+	return repo.FindOne(ctx, bson.M{"email": email}, nil)
+}
 
-	// Create new User instance
+// saveUser creates a new user
+func saveUser(ctx context.Context, userData *CreateUserDTO) (*user.User, error) {
 	newUser := &user.User{
 		Name:  userData.Name,
 		Email: userData.Email,
 	}
 
-	// Save using base repository
-	err := repo.Save(ctx, newUser)
-	if err != nil {
+	if err := repo.Create(ctx, newUser); err != nil {
 		return nil, err
 	}
 
 	return newUser, nil
 }
 
-// UpdateUser updates an existing user
-func UpdateUser(id string, updateData bson.M) error {
-	ctx := context.Background()
-
-	// Convert string ID to ObjectID
-	objectID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		return err
-	}
-
-	filter := bson.M{"_id": objectID}
-	update := bson.M{"$set": updateData}
-
-	_, err = repo.UpdateOne(ctx, &user.User{}, filter, update)
-	return err
+// UpdateUser sets fields of an existing user and returns the updated user
+func UpdateUser(ctx context.Context, id string, updateData bson.M) (*user.User, error) {
+	updateData["updated_at"] = time.Now()
+	return repo.UpdateByID(ctx, id, bson.M{"$set": updateData})
 }
 
-// DeleteUser deletes a user by ID
-func DeleteUser(id string) error {
-	ctx := context.Background()
+// UpdateUserWithVersion is UpdateUser for a user read at version; a user
+// changed since is a *querybuilder.ConflictError
+func UpdateUserWithVersion(ctx context.Context, id string, version int64, updateData bson.M) (*user.User, error) {
+	updateData["updated_at"] = time.Now()
+	return repo.UpdateByIDWithVersion(ctx, id, version, bson.M{"$set": updateData})
+}
 
-	// Convert string ID to ObjectID
-	objectID, err := primitive.ObjectIDFromHex(id)
-	if err != nil {
-		return err
-	}
+// DeleteUser soft-deletes a user by ID on behalf of the request principal in ctx
+func DeleteUser(ctx context.Context, id string) error {
+	return repo.Delete(ctx, id)
+}
 
-	filter := bson.M{"_id": objectID}
-	_, err = repo.DeleteOne(ctx, &user.User{}, filter)
-	return err
+// RestoreUser restores a soft-deleted user by ID
+func RestoreUser(ctx context.Context, id string) error {
+	return repo.Restore(ctx, id)
 }
 
 // CountUsers counts users matching the filter
-func CountUsers(filter bson.M) (int64, error) {
-	ctx := context.Background()
-	return repo.CountDocuments(ctx, &user.User{}, filter)
+func CountUsers(filter interface{}) (int64, error) {
+	return repo.Count(context.Background(), filter)
 }
 
-// FindUsersWithPagination retrieves users with pagination
-func FindUsersWithPagination(filter bson.M, page, limit int) (*querybuilder.PaginateResult, error) {
-	ctx := context.Background()
-	users := []user.User{}
+// FindUsersWithPagination retrieves one page of users
+func FindUsersWithPagination(ctx context.Context, filter interface{}, opts querybuilder.PaginateOptions) (*querybuilder.Page[*user.User], error) {
+	return repo.Paginate(ctx, filter, opts)
+}
 
-	opts := querybuilder.PaginateOptions{
-		Page:  page,
-		Limit: limit,
+// SearchUsersWithPagination is SearchUsers with page/limit pagination
+func SearchUsersWithPagination(ctx context.Context, index, query string, filter interface{}, opts querybuilder.PaginateOptions) (*querybuilder.Page[*user.User], error) {
+	pipeline := mongo.Pipeline{
+		querybuilder.SearchStage(index, query, "name", "email"),
+		{{Key: "$match", Value: filter}},
 	}
-
-	result, err := repo.FindWithPagination(ctx, &user.User{}, &users, filter, opts)
-	if err != nil {
-		return nil, err
+	if opts.Sort != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$sort", Value: opts.Sort}})
 	}
-
-	return result, nil
+	if opts.Projection != nil {
+		pipeline = append(pipeline, bson.D{{Key: "$project", Value: opts.Projection}})
+	}
+	return repo.PaginatePipeline(ctx, pipeline, opts)
 }
@@ -6,6 +6,10 @@ import (
 )
 
 func Routes(r fiber.Router) {
-	r.Post("/users", middleware.BasicAuth(), Create)
-	r.Get("/users", middleware.BasicAuth(), List)
+	r.Post("/users", middleware.BasicAuth("users:write"), middleware.RateLimit("users"), Create)
+	r.Get("/users", middleware.APIKeyOrBasicAuth("users:read"), middleware.RateLimit("users"), List)
+	r.Get("/users/:id", middleware.APIKeyOrBasicAuth("users:read"), middleware.RateLimit("users"), Get)
+	r.Patch("/users/:id", middleware.BasicAuth("users:write"), middleware.RateLimit("users"), Update)
+	r.Delete("/users/:id", middleware.BasicAuth("users:write"), middleware.RateLimit("users"), Delete)
+	r.Post("/users/:id/restore", middleware.BasicAuth("users:admin"), middleware.RateLimit("users"), Restore)
 }
@@ -1,15 +1,117 @@
 package userv1
 
 import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/addixit1/fiber-boilerplate/internal/config"
 	"github.com/addixit1/fiber-boilerplate/internal/modules/user"
+	"github.com/addixit1/fiber-boilerplate/internal/querybuilder"
 	"go.mongodb.org/mongo-driver/bson"
 )
 
-func ListUsers(filter bson.M) ([]user.User, error) {
-	return FindUsers(filter)
+// searchMode is how applySearch matches users
+type searchMode int
+
+const (
+	searchNone    searchMode = iota
+	searchAtlas              // Atlas Search index; the caller builds the pipeline
+	searchText               // text index; sort by score where possible
+	searchPattern            // literal match on name or email
+)
+
+// applySearch adds search to filter and reports how it matches. Search
+// uses the Atlas Search index when MONGO_SEARCH_INDEX is set, then the text
+// index, and falls back to a literal match on the name or email.
+func applySearch(ctx context.Context, search string, filter *querybuilder.Builder) (string, searchMode) {
+	search = strings.TrimSpace(search)
+	switch {
+	case search == "":
+		return search, searchNone
+	case config.Config.Mongo.SearchIndex != "":
+		return search, searchAtlas
+	case querybuilder.HasTextIndex(ctx, &user.User{}):
+		filter.Text(search)
+		return search, searchText
+	default:
+		filter.Or(querybuilder.New().Contains("name", search), querybuilder.New().Contains("email", search))
+		return search, searchPattern
+	}
+}
+
+// ListUsers returns the users matching search and the parsed query string,
+// best matches first when searching the text index
+func ListUsers(ctx context.Context, search string, query *querybuilder.Query) ([]*user.User, error) {
+	opts := query.FindOptions()
+	search, mode := applySearch(ctx, search, query.Filter)
+	switch mode {
+	case searchAtlas:
+		return SearchUsers(ctx, config.Config.Mongo.SearchIndex, search, query.Filter.Build(), opts)
+	case searchText:
+		opts.TextScore = true
+	}
+	return FindUsers(ctx, query.Filter.Build(), opts)
+}
+
+// ListUsersByPage is ListUsers with page/limit pagination
+func ListUsersByPage(ctx context.Context, search string, query *querybuilder.Query, page, limit int) (*querybuilder.Page[*user.User], error) {
+	opts := query.PaginateOptions(page, limit)
+	search, mode := applySearch(ctx, search, query.Filter)
+	switch mode {
+	case searchAtlas:
+		return SearchUsersWithPagination(ctx, config.Config.Mongo.SearchIndex, search, query.Filter.Build(), opts)
+	case searchText:
+		opts.TextScore = true
+	}
+	return FindUsersWithPagination(ctx, query.Filter.Build(), opts)
 }
 
-func CreateUser(user *CreateUserDTO) error {
-	_, err := saveUser(user)
-	return err
+// ListUsersByCursor is ListUsers with keyset pagination. Results are
+// ordered by the query's first sort field rather than by text score.
+func ListUsersByCursor(ctx context.Context, search string, query *querybuilder.Query, cursor string, limit int) ([]*user.User, *querybuilder.CursorPage, error) {
+	opts := query.CursorOptions(cursor, limit)
+	search, mode := applySearch(ctx, search, query.Filter)
+	if mode == searchAtlas {
+		return SearchUsersWithCursor(ctx, config.Config.Mongo.SearchIndex, search, query.Filter.Build(), opts)
+	}
+	return FindUsersWithCursor(ctx, query.Filter.Build(), opts)
+}
+
+// ErrEmailTaken is returned by CreateUser when a user already has the email
+var ErrEmailTaken = errors.New("email already registered")
+
+// CreateUser saves a new user in a transaction, so the email check, the
+// user and its audit entry commit together. On a standalone mongod, as in
+// local development, it runs without one.
+func CreateUser(ctx context.Context, data *CreateUserDTO) error {
+	return querybuilder.RunInTransaction(ctx, func(ctx context.Context) error {
+		_, err := FindUserByEmail(ctx, data.Email)
+		if err == nil {
+			return ErrEmailTaken
+		}
+		if !querybuilder.IsNotFound(err) {
+			return err
+		}
+
+		_, err = saveUser(ctx, data)
+		return err
+	}, querybuilder.TxOptions{AllowStandalone: true})
+}
+
+// EditUser applies the fields set in data to a user. When version is not
+// nil the update only applies if the user is still at that version.
+func EditUser(ctx context.Context, id string, data *UpdateUserDTO, version *int64) (*user.User, error) {
+	updateData := bson.M{}
+	if data.Name != nil {
+		updateData["name"] = *data.Name
+	}
+	if data.Email != nil {
+		updateData["email"] = *data.Email
+	}
+
+	if version == nil {
+		return UpdateUser(ctx, id, updateData)
+	}
+	return UpdateUserWithVersion(ctx, id, *version, updateData)
 }
@@ -2,6 +2,7 @@ package querybuilder
 
 import (
 	"context"
+	"slices"
 	"time"
 
 	"github.com/kamva/mgm/v3"
@@ -20,27 +21,73 @@ func NewBaseRepository() *BaseRepository {
 
 // FindOptions holds options for Find queries
 type FindOptions struct {
-	Sort       bson.M
-	Projection bson.M
+	// Sort is a bson.D for multi-field sorts; the keys of a bson.M have no
+	// order, so they are applied alphabetically
+	Sort       interface{}
+	Projection interface{}
 	Skip       *int64
 	Limit      *int64
+	Hint       interface{}
 	Populate   []string // For future population support
+
+	// TextScore sorts $text results by relevance before Sort
+	TextScore bool
+}
+
+// sort returns the sort document, with the text score first when requested
+func (o *FindOptions) sort() interface{} {
+	sort := bson.D{}
+	if o.TextScore {
+		sort = append(sort, TextScoreSort)
+	}
+
+	switch typed := o.Sort.(type) {
+	case nil:
+	case bson.D:
+		sort = append(sort, typed...)
+	case bson.M:
+		keys := make([]string, 0, len(typed))
+		for key := range typed {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			sort = append(sort, bson.E{Key: key, Value: typed[key]})
+		}
+	default:
+		// Other documents, e.g. a struct, are passed to the driver as they are
+		if !o.TextScore {
+			return o.Sort
+		}
+	}
+
+	if len(sort) == 0 {
+		return nil
+	}
+	return sort
 }
 
 // PaginateOptions holds pagination parameters
 type PaginateOptions struct {
 	Page  int
 	Limit int
+
+	// Count selects exact, estimated or no total
+	Count CountMode
+	// Hint names the index to count and fetch with, e.g. "users_created_at"
+	Hint interface{}
+
+	// Sort, Projection and TextScore apply to FindWithPagination;
+	// pipelines sort themselves
+	Sort       interface{}
+	Projection interface{}
+	TextScore  bool
 }
 
 // PaginateResult holds paginated query results
 type PaginateResult struct {
-	Data      interface{}
-	Total     int64
-	Page      int
-	Limit     int
-	TotalPage int
-	NextPage  int
+	Data interface{}
+	PageInfo
 }
 
 // Save creates a new document
@@ -48,11 +95,16 @@ func (r *BaseRepository) Save(ctx context.Context, model mgm.Model) error {
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	return mgm.Coll(model).CreateWithCtx(ctx, model)
+	t := track(ctx, model, nil, false)
+	if err := mgm.Coll(model).CreateWithCtx(ctx, model); err != nil {
+		return err
+	}
+	t.written(ActionCreate, model)
+	return nil
 }
 
 // Find retrieves multiple documents with optional filters and options
-func (r *BaseRepository) Find(ctx context.Context, model mgm.Model, results interface{}, filter bson.M, opts *FindOptions) error {
+func (r *BaseRepository) Find(ctx context.Context, model mgm.Model, results interface{}, filter interface{}, opts *FindOptions) error {
 	if ctx == nil {
 		ctx = context.Background()
 	}
@@ -61,8 +113,8 @@ func (r *BaseRepository) Find(ctx context.Context, model mgm.Model, results inte
 	findOpts := options.Find()
 
 	if opts != nil {
-		if opts.Sort != nil {
-			findOpts.SetSort(opts.Sort)
+		if sort := opts.sort(); sort != nil {
+			findOpts.SetSort(sort)
 		}
 		if opts.Projection != nil {
 			findOpts.SetProjection(opts.Projection)
@@ -73,9 +125,12 @@ func (r *BaseRepository) Find(ctx context.Context, model mgm.Model, results inte
 		if opts.Limit != nil {
 			findOpts.SetLimit(*opts.Limit)
 		}
+		if opts.Hint != nil {
+			findOpts.SetHint(opts.Hint)
+		}
 	}
 
-	cursor, err := coll.Find(ctx, filter, findOpts)
+	cursor, err := coll.Find(ctx, scope(ctx, model, filter), findOpts)
 	if err != nil {
 		return err
 	}
@@ -85,7 +140,7 @@ func (r *BaseRepository) Find(ctx context.Context, model mgm.Model, results inte
 }
 
 // FindOne retrieves a single document
-func (r *BaseRepository) FindOne(ctx context.Context, model mgm.Model, filter bson.M, opts *FindOptions) error {
+func (r *BaseRepository) FindOne(ctx context.Context, model mgm.Model, filter interface{}, opts *FindOptions) error {
 	if ctx == nil {
 		ctx = context.Background()
 	}
@@ -94,15 +149,15 @@ func (r *BaseRepository) FindOne(ctx context.Context, model mgm.Model, filter bs
 	findOpts := options.FindOne()
 
 	if opts != nil {
-		if opts.Sort != nil {
-			findOpts.SetSort(opts.Sort)
+		if sort := opts.sort(); sort != nil {
+			findOpts.SetSort(sort)
 		}
 		if opts.Projection != nil {
 			findOpts.SetProjection(opts.Projection)
 		}
 	}
 
-	return coll.FindOne(ctx, filter, findOpts).Decode(model)
+	return coll.FindOne(ctx, scope(ctx, model, filter), findOpts).Decode(model)
 }
 
 // FindById retrieves a document by ID
@@ -110,89 +165,168 @@ func (r *BaseRepository) FindById(ctx context.Context, model mgm.Model, id inter
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	return mgm.Coll(model).FindByIDWithCtx(ctx, id, model)
+	id, err := model.PrepareID(id)
+	if err != nil {
+		return err
+	}
+	return r.FindOne(ctx, model, bson.M{"_id": id}, nil)
 }
 
 // UpdateOne updates a single document
-func (r *BaseRepository) UpdateOne(ctx context.Context, model mgm.Model, filter bson.M, update bson.M) (*mongo.UpdateResult, error) {
+func (r *BaseRepository) UpdateOne(ctx context.Context, model mgm.Model, filter interface{}, update bson.M) (*mongo.UpdateResult, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	return mgm.Coll(model).UpdateOne(ctx, filter, update)
+	filter = scope(ctx, model, filter)
+	t := track(ctx, model, filter, false)
+	result, err := mgm.Coll(model).UpdateOne(ctx, filter, bumpVersion(model, update))
+	if err != nil {
+		return nil, err
+	}
+	t.reloaded(ActionUpdate)
+	return result, nil
 }
 
 // UpdateMany updates multiple documents
-func (r *BaseRepository) UpdateMany(ctx context.Context, model mgm.Model, filter bson.M, update bson.M) (*mongo.UpdateResult, error) {
+func (r *BaseRepository) UpdateMany(ctx context.Context, model mgm.Model, filter interface{}, update bson.M) (*mongo.UpdateResult, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	return mgm.Coll(model).UpdateMany(ctx, filter, update)
+	filter = scope(ctx, model, filter)
+	t := track(ctx, model, filter, true)
+	result, err := mgm.Coll(model).UpdateMany(ctx, filter, bumpVersion(model, update))
+	if err != nil {
+		return nil, err
+	}
+	t.reloaded(ActionUpdate)
+	return result, nil
 }
 
 // FindOneAndUpdate finds and updates a single document
-func (r *BaseRepository) FindOneAndUpdate(ctx context.Context, model mgm.Model, filter bson.M, update bson.M, opts *options.FindOneAndUpdateOptions) error {
+func (r *BaseRepository) FindOneAndUpdate(ctx context.Context, model mgm.Model, filter interface{}, update bson.M, opts *options.FindOneAndUpdateOptions) error {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
-	coll := mgm.Coll(model)
-	return coll.FindOneAndUpdate(ctx, filter, update, opts).Decode(model)
+	filter = scope(ctx, model, filter)
+	t := track(ctx, model, filter, false)
+	if err := mgm.Coll(model).FindOneAndUpdate(ctx, filter, bumpVersion(model, update), opts).Decode(model); err != nil {
+		return err
+	}
+	t.reloaded(ActionUpdate)
+	return nil
 }
 
-// UpdateById updates a document by ID
+// UpdateById saves model like mgm's UpdateWithCtx and returns
+// mongo.ErrNoDocuments when it does not exist or is soft-deleted.
+// Versioned models are only written over the version they were read at;
+// see VersionedModel.
 func (r *BaseRepository) UpdateById(ctx context.Context, model mgm.Model) error {
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	return mgm.Coll(model).UpdateWithCtx(ctx, model)
+	if versioned, ok := model.(Versioned); ok {
+		return r.updateVersioned(ctx, model, versioned)
+	}
+	if err := beforeUpdate(ctx, model); err != nil {
+		return err
+	}
+
+	byID := bson.M{"_id": model.GetID()}
+	t := track(ctx, model, byID, false)
+	result, err := mgm.Coll(model).UpdateOne(ctx, scope(ctx, model, byID), bson.M{"$set": model})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	t.written(ActionUpdate, model)
+	return afterUpdate(ctx, result, model)
 }
 
-// DeleteOne deletes a single document
-func (r *BaseRepository) DeleteOne(ctx context.Context, model mgm.Model, filter bson.M) (*mongo.DeleteResult, error) {
+// DeleteOne deletes a single document; soft-deletable models are only marked deleted
+func (r *BaseRepository) DeleteOne(ctx context.Context, model mgm.Model, filter interface{}) (*mongo.DeleteResult, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	return mgm.Coll(model).DeleteOne(ctx, filter)
+	if IsSoftDeletable(model) {
+		return r.softDelete(ctx, model, filter, false)
+	}
+
+	t := track(ctx, model, filter, false)
+	result, err := mgm.Coll(model).DeleteOne(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	t.removed()
+	return result, nil
 }
 
-// DeleteMany deletes multiple documents
-func (r *BaseRepository) DeleteMany(ctx context.Context, model mgm.Model, filter bson.M) (*mongo.DeleteResult, error) {
+// DeleteMany deletes multiple documents; soft-deletable models are only marked deleted
+func (r *BaseRepository) DeleteMany(ctx context.Context, model mgm.Model, filter interface{}) (*mongo.DeleteResult, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	return mgm.Coll(model).DeleteMany(ctx, filter)
+	if IsSoftDeletable(model) {
+		return r.softDelete(ctx, model, filter, true)
+	}
+
+	t := track(ctx, model, filter, true)
+	result, err := mgm.Coll(model).DeleteMany(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	t.removed()
+	return result, nil
 }
 
-// DeleteById deletes a document by ID
+// DeleteById deletes a document by ID; soft-deletable models are only marked deleted
 func (r *BaseRepository) DeleteById(ctx context.Context, model mgm.Model) error {
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	return mgm.Coll(model).DeleteWithCtx(ctx, model)
+
+	deletable, ok := model.(SoftDeletable)
+	if !ok {
+		t := track(ctx, model, bson.M{"_id": model.GetID()}, false)
+		if err := mgm.Coll(model).DeleteWithCtx(ctx, model); err != nil {
+			return err
+		}
+		t.removed()
+		return nil
+	}
+
+	now, by := time.Now(), actorOf(ctx)
+	if _, err := r.softDelete(ctx, model, bson.M{"_id": model.GetID()}, false); err != nil {
+		return err
+	}
+	deletable.markDeleted(&now, by)
+	return nil
 }
 
 // Count counts documents matching the filter
-func (r *BaseRepository) Count(ctx context.Context, model mgm.Model, filter bson.M) (int64, error) {
+func (r *BaseRepository) Count(ctx context.Context, model mgm.Model, filter interface{}) (int64, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	return mgm.Coll(model).CountDocuments(ctx, filter)
+	return mgm.Coll(model).CountDocuments(ctx, scope(ctx, model, filter))
 }
 
 // CountDocuments counts documents (recommended method)
-func (r *BaseRepository) CountDocuments(ctx context.Context, model mgm.Model, filter bson.M) (int64, error) {
+func (r *BaseRepository) CountDocuments(ctx context.Context, model mgm.Model, filter interface{}) (int64, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	return mgm.Coll(model).CountDocuments(ctx, filter)
+	return mgm.Coll(model).CountDocuments(ctx, scope(ctx, model, filter))
 }
 
 // Distinct returns distinct values for a field
-func (r *BaseRepository) Distinct(ctx context.Context, model mgm.Model, field string, filter bson.M) ([]interface{}, error) {
+func (r *BaseRepository) Distinct(ctx context.Context, model mgm.Model, field string, filter interface{}) ([]interface{}, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
-	return mgm.Coll(model).Distinct(ctx, field, filter)
+	return mgm.Coll(model).Distinct(ctx, field, scope(ctx, model, filter))
 }
 
 // Aggregate runs an aggregation pipeline
@@ -202,7 +336,7 @@ func (r *BaseRepository) Aggregate(ctx context.Context, model mgm.Model, pipelin
 	}
 
 	coll := mgm.Coll(model)
-	cursor, err := coll.Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+	cursor, err := coll.Aggregate(ctx, scopePipeline(ctx, model, pipeline), options.Aggregate().SetAllowDiskUse(true))
 	if err != nil {
 		return err
 	}
@@ -211,84 +345,89 @@ func (r *BaseRepository) Aggregate(ctx context.Context, model mgm.Model, pipelin
 	return cursor.All(ctx, results)
 }
 
-// Paginate performs pagination with aggregation pipeline
+// Paginate runs pipeline with pagination. Data and count come from a single
+// $facet aggregation, so the page must fit in one 16MB document.
 func (r *BaseRepository) Paginate(ctx context.Context, model mgm.Model, pipeline mongo.Pipeline, opts PaginateOptions) (*PaginateResult, error) {
-	if ctx == nil {
-		ctx = context.Background()
+	data := []bson.M{}
+	info, err := r.paginatePipeline(ctx, model, pipeline, &data, opts)
+	if err != nil {
+		return nil, err
 	}
+	return &PaginateResult{Data: data, PageInfo: info}, nil
+}
 
-	// Validate and set defaults
-	if opts.Limit <= 0 {
-		opts.Limit = 10
-	}
-	if opts.Limit > 100 {
-		opts.Limit = 100
-	}
-	if opts.Page <= 0 {
-		opts.Page = 1
+// paginatePipeline runs Paginate's $facet aggregation, decoding the page into results
+func (r *BaseRepository) paginatePipeline(ctx context.Context, model mgm.Model, pipeline mongo.Pipeline, results interface{}, opts PaginateOptions) (PageInfo, error) {
+	if ctx == nil {
+		ctx = context.Background()
 	}
 
-	// Calculate skip
+	opts = normalizePaginateOptions(opts)
 	skip := (opts.Page - 1) * opts.Limit
+	pipeline = scopePipeline(ctx, model, pipeline)
 
-	// Create a copy of pipeline for counting
-	countPipeline := append(mongo.Pipeline{}, pipeline...)
-	countPipeline = append(countPipeline, bson.D{{Key: "$count", Value: "total"}})
-
-	// Add pagination to main pipeline
-	paginatedPipeline := append(pipeline,
+	// Fetch one extra to check if there's a next page
+	facet := bson.D{{Key: "data", Value: bson.A{
 		bson.D{{Key: "$skip", Value: skip}},
-		bson.D{{Key: "$limit", Value: opts.Limit + 1}}, // Fetch one extra to check if there's a next page
-	)
+		bson.D{{Key: "$limit", Value: opts.Limit + 1}},
+	}}}
+	estimate := opts.Count == CountEstimated && len(pipeline) == 0
+	counted := opts.Count != CountNone && !estimate
+	if counted {
+		facet = append(facet, bson.E{Key: "total", Value: bson.A{bson.D{{Key: "$count", Value: "total"}}}})
+	}
 
-	coll := mgm.Coll(model)
+	paginatedPipeline := append(mongo.Pipeline{}, pipeline...)
+	paginatedPipeline = append(paginatedPipeline, bson.D{{Key: "$facet", Value: facet}})
 
-	// Execute count query
-	var countResult []bson.M
-	countCursor, err := coll.Aggregate(ctx, countPipeline, options.Aggregate().SetAllowDiskUse(true))
-	if err != nil {
-		return nil, err
-	}
-	if err := countCursor.All(ctx, &countResult); err != nil {
-		return nil, err
+	aggOpts := options.Aggregate().SetAllowDiskUse(true)
+	if opts.Hint != nil {
+		aggOpts.SetHint(opts.Hint)
 	}
 
-	var total int64
-	if len(countResult) > 0 {
-		if t, ok := countResult[0]["total"].(int32); ok {
-			total = int64(t)
-		} else if t, ok := countResult[0]["total"].(int64); ok {
-			total = t
-		}
+	coll := mgm.Coll(model)
+	cursor, err := coll.Aggregate(ctx, paginatedPipeline, aggOpts)
+	if err != nil {
+		return PageInfo{}, err
 	}
+	defer cursor.Close(ctx)
 
-	// Execute data query
-	var data []bson.M
-	dataCursor, err := coll.Aggregate(ctx, paginatedPipeline, options.Aggregate().SetAllowDiskUse(true))
-	if err != nil {
-		return nil, err
+	var facets []struct {
+		Data  []bson.Raw `bson:"data"`
+		Total []struct {
+			Total int64 `bson:"total"`
+		} `bson:"total"`
+	}
+	if err := cursor.All(ctx, &facets); err != nil {
+		return PageInfo{}, err
+	}
+
+	var data []bson.Raw
+	total := int64(-1)
+	if len(facets) > 0 {
+		data = facets[0].Data
+		if counted {
+			total = 0
+			if len(facets[0].Total) > 0 {
+				total = facets[0].Total[0].Total
+			}
+		}
 	}
-	if err := dataCursor.All(ctx, &data); err != nil {
-		return nil, err
+	if estimate {
+		if total, err = coll.EstimatedDocumentCount(ctx); err != nil {
+			return PageInfo{}, err
+		}
 	}
 
-	// Check if there's a next page
-	nextPage := 0
-	if len(data) > opts.Limit {
-		nextPage = opts.Page + 1
+	hasNext := len(data) > opts.Limit
+	if hasNext {
 		data = data[:opts.Limit] // Remove the extra record
 	}
+	if err := decodeInto(data, results); err != nil {
+		return PageInfo{}, err
+	}
 
-	totalPage := int((total + int64(opts.Limit) - 1) / int64(opts.Limit))
-
-	return &PaginateResult{
-		Data:      data,
-		Total:     total,
-		Page:      opts.Page,
-		Limit:     opts.Limit,
-		TotalPage: totalPage,
-		NextPage:  nextPage,
-	}, nil
+	return newPageInfo(total, opts.Page, opts.Limit, hasNext), nil
 }
 
 // InsertMany inserts multiple documents
@@ -308,58 +447,36 @@ func (r *BaseRepository) BulkWrite(ctx context.Context, model mgm.Model, operati
 }
 
 // FindWithPagination performs a simple find with pagination (non-aggregation)
-func (r *BaseRepository) FindWithPagination(ctx context.Context, model mgm.Model, results interface{}, filter bson.M, opts PaginateOptions) (*PaginateResult, error) {
+func (r *BaseRepository) FindWithPagination(ctx context.Context, model mgm.Model, results interface{}, filter interface{}, opts PaginateOptions) (*PaginateResult, error) {
 	if ctx == nil {
 		ctx = context.Background()
 	}
 
-	// Validate and set defaults
-	if opts.Limit <= 0 {
-		opts.Limit = 10
-	}
-	if opts.Limit > 100 {
-		opts.Limit = 100
-	}
-	if opts.Page <= 0 {
-		opts.Page = 1
-	}
-
-	// Calculate skip
+	opts = normalizePaginateOptions(opts)
 	skip := int64((opts.Page - 1) * opts.Limit)
-	limit := int64(opts.Limit + 1) // Fetch one extra
+	limit := int64(opts.Limit + 1) // Fetch one extra to check if there's a next page
 
-	// Get total count
-	total, err := r.CountDocuments(ctx, model, filter)
+	total, err := r.count(ctx, model, filter, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	// Fetch data
 	findOpts := &FindOptions{
-		Skip:  &skip,
-		Limit: &limit,
-	}
-
-	err = r.Find(ctx, model, results, filter, findOpts)
-	if err != nil {
+		Sort:       opts.Sort,
+		Projection: opts.Projection,
+		Skip:       &skip,
+		Limit:      &limit,
+		Hint:       opts.Hint,
+		TextScore:  opts.TextScore,
+	}
+	if err := r.Find(ctx, model, results, filter, findOpts); err != nil {
 		return nil, err
 	}
-
-	// Check for next page (this requires reflection or a different approach based on results type)
-	// For simplicity, we'll calculate it based on total
-	totalPage := int((total + int64(opts.Limit) - 1) / int64(opts.Limit))
-	nextPage := 0
-	if opts.Page < totalPage {
-		nextPage = opts.Page + 1
-	}
+	hasNext := trimResults(results, opts.Limit)
 
 	return &PaginateResult{
-		Data:      results,
-		Total:     total,
-		Page:      opts.Page,
-		Limit:     opts.Limit,
-		TotalPage: totalPage,
-		NextPage:  nextPage,
+		Data:     results,
+		PageInfo: newPageInfo(total, opts.Page, opts.Limit, hasNext),
 	}, nil
 }
 
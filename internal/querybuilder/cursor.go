@@ -0,0 +1,323 @@
+package querybuilder
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrInvalidCursor is returned for cursors that do not decode, were issued
+// for another sort field, or hold values a cursor never carries. Cursors are
+// not signed: a client can point one anywhere in the sort order, but only
+// at a plain value.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// CursorOptions holds keyset pagination parameters. Results are ordered by
+// SortField with _id as tie-breaker, so SortField should be indexed
+// together with _id.
+type CursorOptions struct {
+	// Cursor is NextCursor or PrevCursor of a previous page; "" starts at the beginning
+	Cursor string
+	Limit  int
+
+	// SortField defaults to _id
+	SortField  string
+	Descending bool
+
+	// Projection must keep SortField; it is added to inclusion projections
+	Projection bson.D
+}
+
+// CursorPage describes the position of a page of keyset results
+type CursorPage struct {
+	NextCursor string
+	PrevCursor string
+	HasNext    bool
+	HasPrev    bool
+	Limit      int
+}
+
+// cursorToken is the decoded form of an opaque cursor
+type cursorToken struct {
+	Field string        `bson:"f"`
+	Value bson.RawValue `bson:"v"`
+	ID    bson.RawValue `bson:"id"`
+	Prev  bool          `bson:"p,omitempty"`
+}
+
+// FindWithCursor retrieves one page of the documents matching filter using
+// keyset pagination, which stays fast on large collections and does not
+// repeat or skip documents inserted between pages
+func (r *BaseRepository) FindWithCursor(ctx context.Context, model mgm.Model, results interface{}, filter interface{}, opts CursorOptions) (*CursorPage, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	opts = normalizeCursorOptions(opts)
+	token, err := decodeCursor(opts.Cursor, opts.SortField)
+	if err != nil {
+		return nil, err
+	}
+
+	filter = scope(ctx, model, filter)
+	if token != nil {
+		keyset := keysetFilter(opts, token)
+		if isEmptyFilter(filter) {
+			filter = keyset
+		} else {
+			filter = bson.D{{Key: "$and", Value: bson.A{filter, keyset}}}
+		}
+	}
+
+	findOpts := options.Find().
+		SetSort(cursorSort(opts, token)).
+		SetLimit(int64(opts.Limit + 1))
+	if len(opts.Projection) > 0 {
+		findOpts.SetProjection(cursorProjection(opts))
+	}
+
+	cursor, err := mgm.Coll(model).Find(ctx, filter, findOpts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	return readCursorPage(ctx, cursor, results, opts, token)
+}
+
+// AggregateWithCursor is FindWithCursor for aggregation pipelines. The
+// keyset $match, $sort and $limit stages are appended to pipeline, so its
+// output must contain SortField and _id.
+func (r *BaseRepository) AggregateWithCursor(ctx context.Context, model mgm.Model, pipeline mongo.Pipeline, results interface{}, opts CursorOptions) (*CursorPage, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	opts = normalizeCursorOptions(opts)
+	token, err := decodeCursor(opts.Cursor, opts.SortField)
+	if err != nil {
+		return nil, err
+	}
+
+	paged := append(mongo.Pipeline{}, scopePipeline(ctx, model, pipeline)...)
+	if token != nil {
+		paged = append(paged, bson.D{{Key: "$match", Value: keysetFilter(opts, token)}})
+	}
+	paged = append(paged,
+		bson.D{{Key: "$sort", Value: cursorSort(opts, token)}},
+		bson.D{{Key: "$limit", Value: opts.Limit + 1}},
+	)
+	if len(opts.Projection) > 0 {
+		paged = append(paged, bson.D{{Key: "$project", Value: cursorProjection(opts)}})
+	}
+
+	cursor, err := mgm.Coll(model).Aggregate(ctx, paged, options.Aggregate().SetAllowDiskUse(true))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	return readCursorPage(ctx, cursor, results, opts, token)
+}
+
+// normalizeCursorOptions applies the same limit bounds as Paginate
+func normalizeCursorOptions(opts CursorOptions) CursorOptions {
+	if opts.Limit <= 0 {
+		opts.Limit = 10
+	}
+	if opts.Limit > 100 {
+		opts.Limit = 100
+	}
+	if opts.SortField == "" {
+		opts.SortField = "_id"
+	}
+	return opts
+}
+
+// cursorSort orders by the sort field and _id, reversed when paging backwards
+func cursorSort(opts CursorOptions, token *cursorToken) bson.D {
+	direction := 1
+	if opts.Descending {
+		direction = -1
+	}
+	if token != nil && token.Prev {
+		direction = -direction
+	}
+
+	if opts.SortField == "_id" {
+		return bson.D{{Key: "_id", Value: direction}}
+	}
+	return bson.D{{Key: opts.SortField, Value: direction}, {Key: "_id", Value: direction}}
+}
+
+// keysetFilter matches the documents after (or, paging backwards, before) the cursor
+func keysetFilter(opts CursorOptions, token *cursorToken) bson.D {
+	operator := "$gt"
+	if opts.Descending != token.Prev {
+		operator = "$lt"
+	}
+
+	if opts.SortField == "_id" {
+		return bson.D{{Key: "_id", Value: bson.D{{Key: operator, Value: token.ID}}}}
+	}
+	return bson.D{{Key: "$or", Value: bson.A{
+		bson.D{{Key: opts.SortField, Value: bson.D{{Key: operator, Value: token.Value}}}},
+		bson.D{
+			{Key: opts.SortField, Value: bson.D{{Key: "$eq", Value: token.Value}}},
+			{Key: "_id", Value: bson.D{{Key: operator, Value: token.ID}}},
+		},
+	}}}
+}
+
+// cursorProjection adds the sort field to inclusion projections
+func cursorProjection(opts CursorOptions) bson.D {
+	projection := append(bson.D{}, opts.Projection...)
+	for _, e := range projection {
+		if e.Key == opts.SortField || e.Value == 0 || e.Value == false {
+			return projection
+		}
+	}
+	return append(projection, bson.E{Key: opts.SortField, Value: 1})
+}
+
+// readCursorPage decodes a page into results and builds its cursors
+func readCursorPage(ctx context.Context, cursor *mongo.Cursor, results interface{}, opts CursorOptions, token *cursorToken) (*CursorPage, error) {
+	var docs []bson.Raw
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	page := &CursorPage{Limit: opts.Limit}
+	more := len(docs) > opts.Limit
+	if more {
+		docs = docs[:opts.Limit]
+	}
+
+	backwards := token != nil && token.Prev
+	if backwards {
+		// Backward pages are read in reverse order
+		for i, j := 0, len(docs)-1; i < j; i, j = i+1, j-1 {
+			docs[i], docs[j] = docs[j], docs[i]
+		}
+		page.HasPrev, page.HasNext = more, true
+	} else {
+		page.HasNext, page.HasPrev = more, token != nil
+	}
+
+	if len(docs) > 0 {
+		var err error
+		if page.HasNext {
+			if page.NextCursor, err = encodeCursor(docs[len(docs)-1], opts.SortField, false); err != nil {
+				return nil, err
+			}
+		}
+		if page.HasPrev {
+			if page.PrevCursor, err = encodeCursor(docs[0], opts.SortField, true); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return page, decodeInto(docs, results)
+}
+
+// encodeCursor builds the opaque cursor pointing at doc
+func encodeCursor(doc bson.Raw, field string, prev bool) (string, error) {
+	value, err := doc.LookupErr(strings.Split(field, ".")...)
+	if err != nil {
+		return "", fmt.Errorf("cursor sort field %s missing from results: %w", field, err)
+	}
+	id, err := doc.LookupErr("_id")
+	if err != nil {
+		return "", fmt.Errorf("cursor results have no _id: %w", err)
+	}
+
+	data, err := bson.Marshal(cursorToken{Field: field, Value: value, ID: id, Prev: prev})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor parses a cursor issued for field; "" yields nil
+func decodeCursor(cursor, field string) (*cursorToken, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	token := &cursorToken{}
+	if err := bson.Unmarshal(data, token); err != nil || token.Field != field {
+		return nil, ErrInvalidCursor
+	}
+	if !isCursorValue(token.Value) || !isCursorValue(token.ID) {
+		return nil, ErrInvalidCursor
+	}
+	return token, nil
+}
+
+// isCursorValue reports whether v is a plain value that can be compared in
+// a keyset filter. Documents and arrays are rejected so a crafted cursor
+// cannot smuggle in query operators.
+func isCursorValue(v bson.RawValue) bool {
+	switch v.Type {
+	case bsontype.Double, bsontype.String, bsontype.ObjectID, bsontype.Boolean,
+		bsontype.DateTime, bsontype.Null, bsontype.Int32, bsontype.Timestamp,
+		bsontype.Int64, bsontype.Decimal128:
+		return v.Validate() == nil
+	}
+	return false
+}
+
+// decodeInto unmarshals docs into results, a pointer to a slice of
+// structs or of pointers to structs
+func decodeInto(docs []bson.Raw, results interface{}) error {
+	slice := reflect.ValueOf(results)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("results must be a pointer to a slice, got %T", results)
+	}
+	slice = slice.Elem()
+	elemType := slice.Type().Elem()
+
+	out := reflect.MakeSlice(slice.Type(), 0, len(docs))
+	for _, doc := range docs {
+		if elemType.Kind() == reflect.Ptr {
+			elem := reflect.New(elemType.Elem())
+			if err := bson.Unmarshal(doc, elem.Interface()); err != nil {
+				return err
+			}
+			out = reflect.Append(out, elem)
+			continue
+		}
+		elem := reflect.New(elemType)
+		if err := bson.Unmarshal(doc, elem.Interface()); err != nil {
+			return err
+		}
+		out = reflect.Append(out, elem.Elem())
+	}
+	slice.Set(out)
+	return nil
+}
+
+// isEmptyFilter reports whether filter is an empty bson.M or bson.D
+func isEmptyFilter(filter interface{}) bool {
+	switch typed := filter.(type) {
+	case bson.M:
+		return len(typed) == 0
+	case bson.D:
+		return len(typed) == 0
+	}
+	return false
+}
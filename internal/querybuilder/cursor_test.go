@@ -0,0 +1,236 @@
+package querybuilder
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	id := primitive.NewObjectID()
+	created := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		doc   bson.D
+		field string
+		prev  bool
+		want  interface{}
+	}{
+		{name: "by _id", doc: bson.D{{Key: "_id", Value: id}}, field: "_id", want: id},
+		{name: "by string", doc: bson.D{{Key: "_id", Value: id}, {Key: "name", Value: "ada"}}, field: "name", want: "ada"},
+		{name: "by date", doc: bson.D{{Key: "_id", Value: id}, {Key: "created_at", Value: created}}, field: "created_at", prev: true, want: primitive.NewDateTimeFromTime(created)},
+		{name: "by nested field", doc: bson.D{{Key: "_id", Value: id}, {Key: "address", Value: bson.D{{Key: "city", Value: "Pune"}}}}, field: "address.city", want: "Pune"},
+		{name: "by null", doc: bson.D{{Key: "_id", Value: id}, {Key: "deleted_at", Value: nil}}, field: "deleted_at", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := bson.Marshal(tt.doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cursor, err := encodeCursor(doc, tt.field, tt.prev)
+			if err != nil {
+				t.Fatalf("encodeCursor() error = %v", err)
+			}
+
+			token, err := decodeCursor(cursor, tt.field)
+			if err != nil {
+				t.Fatalf("decodeCursor() error = %v", err)
+			}
+			if token.Prev != tt.prev {
+				t.Errorf("Prev = %v, want %v", token.Prev, tt.prev)
+			}
+			if got := token.ID.ObjectID(); got != id {
+				t.Errorf("ID = %v, want %v", got, id)
+			}
+			var value interface{}
+			if err := token.Value.Unmarshal(&value); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(value, tt.want) {
+				t.Errorf("Value = %#v, want %#v", value, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeCursorMissingFields(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   bson.D
+		field string
+	}{
+		{name: "no sort field", doc: bson.D{{Key: "_id", Value: 1}}, field: "name"},
+		{name: "no _id", doc: bson.D{{Key: "name", Value: "ada"}}, field: "name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := bson.Marshal(tt.doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := encodeCursor(doc, tt.field, false); err == nil {
+				t.Error("encodeCursor() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestDecodeCursorRejects(t *testing.T) {
+	id := primitive.NewObjectID()
+	encode := func(token bson.D) string {
+		data, err := bson.Marshal(token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+
+	tests := []struct {
+		name   string
+		cursor string
+		field  string
+	}{
+		{
+			name:   "not base64",
+			cursor: "!!!",
+			field:  "_id",
+		},
+		{
+			name:   "not bson",
+			cursor: base64.RawURLEncoding.EncodeToString([]byte("cursor")),
+			field:  "_id",
+		},
+		{
+			name:   "issued for another field",
+			cursor: encode(bson.D{{Key: "f", Value: "name"}, {Key: "v", Value: "ada"}, {Key: "id", Value: id}}),
+			field:  "email",
+		},
+		{
+			name:   "operator document as value",
+			cursor: encode(bson.D{{Key: "f", Value: "name"}, {Key: "v", Value: bson.D{{Key: "$ne", Value: nil}}}, {Key: "id", Value: id}}),
+			field:  "name",
+		},
+		{
+			name:   "array as _id",
+			cursor: encode(bson.D{{Key: "f", Value: "name"}, {Key: "v", Value: "ada"}, {Key: "id", Value: bson.A{id}}}),
+			field:  "name",
+		},
+		{
+			name:   "missing _id",
+			cursor: encode(bson.D{{Key: "f", Value: "name"}, {Key: "v", Value: "ada"}}),
+			field:  "name",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeCursor(tt.cursor, tt.field); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("decodeCursor() error = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorEmpty(t *testing.T) {
+	token, err := decodeCursor("", "_id")
+	if token != nil || err != nil {
+		t.Errorf("decodeCursor(\"\") = %v, %v, want nil, nil", token, err)
+	}
+}
+
+func TestKeysetFilter(t *testing.T) {
+	id := bson.RawValue{}
+	value := bson.RawValue{}
+
+	tests := []struct {
+		name string
+		opts CursorOptions
+		prev bool
+		want bson.D
+	}{
+		{
+			name: "_id ascending",
+			opts: CursorOptions{SortField: "_id"},
+			want: bson.D{{Key: "_id", Value: bson.D{{Key: "$gt", Value: id}}}},
+		},
+		{
+			name: "_id descending",
+			opts: CursorOptions{SortField: "_id", Descending: true},
+			want: bson.D{{Key: "_id", Value: bson.D{{Key: "$lt", Value: id}}}},
+		},
+		{
+			name: "field ascending, paging back",
+			opts: CursorOptions{SortField: "name"},
+			prev: true,
+			want: bson.D{{Key: "$or", Value: bson.A{
+				bson.D{{Key: "name", Value: bson.D{{Key: "$lt", Value: value}}}},
+				bson.D{
+					{Key: "name", Value: bson.D{{Key: "$eq", Value: value}}},
+					{Key: "_id", Value: bson.D{{Key: "$lt", Value: id}}},
+				},
+			}}},
+		},
+		{
+			name: "field descending, paging back",
+			opts: CursorOptions{SortField: "name", Descending: true},
+			prev: true,
+			want: bson.D{{Key: "$or", Value: bson.A{
+				bson.D{{Key: "name", Value: bson.D{{Key: "$gt", Value: value}}}},
+				bson.D{
+					{Key: "name", Value: bson.D{{Key: "$eq", Value: value}}},
+					{Key: "_id", Value: bson.D{{Key: "$gt", Value: id}}},
+				},
+			}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := &cursorToken{Field: tt.opts.SortField, Value: value, ID: id, Prev: tt.prev}
+			if got := keysetFilter(tt.opts, token); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("keysetFilter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCursorProjection(t *testing.T) {
+	tests := []struct {
+		name string
+		opts CursorOptions
+		want bson.D
+	}{
+		{
+			name: "inclusion gains the sort field",
+			opts: CursorOptions{SortField: "name", Projection: bson.D{{Key: "email", Value: 1}}},
+			want: bson.D{{Key: "email", Value: 1}, {Key: "name", Value: 1}},
+		},
+		{
+			name: "sort field already included",
+			opts: CursorOptions{SortField: "name", Projection: bson.D{{Key: "name", Value: 1}}},
+			want: bson.D{{Key: "name", Value: 1}},
+		},
+		{
+			name: "exclusion is kept",
+			opts: CursorOptions{SortField: "name", Projection: bson.D{{Key: "password", Value: 0}}},
+			want: bson.D{{Key: "password", Value: 0}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cursorProjection(tt.opts); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("cursorProjection() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package querybuilder
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Action is the kind of write reported to change hooks
+type Action string
+
+const (
+	ActionCreate  Action = "create"
+	ActionUpdate  Action = "update"
+	ActionDelete  Action = "delete"
+	ActionRestore Action = "restore"
+)
+
+// Change is one document written through BaseRepository. Before is nil for
+// creates and After is nil for hard deletes; soft deletes are deletes whose
+// After carries deleted_at.
+type Change struct {
+	Action     Action
+	Collection string
+	DocumentID interface{}
+	Before     bson.M
+	After      bson.M
+}
+
+// ChangeHook is called after a successful write, with the writer's context
+type ChangeHook func(ctx context.Context, change Change)
+
+type registeredHook struct {
+	hook        ChangeHook
+	collections map[string]bool
+}
+
+var hooks struct {
+	sync.RWMutex
+	list []registeredHook
+}
+
+// OnChange registers hook for writes to collections, or to every collection
+// when none are given. Writes to a watched collection load the affected
+// documents before and after the write, which costs an extra read each;
+// both snapshots are best effort and may miss a concurrent write.
+// InsertMany and BulkWrite are not reported.
+func OnChange(hook ChangeHook, collections ...string) {
+	registered := registeredHook{hook: hook}
+	if len(collections) > 0 {
+		registered.collections = make(map[string]bool, len(collections))
+		for _, collection := range collections {
+			registered.collections[collection] = true
+		}
+	}
+
+	hooks.Lock()
+	defer hooks.Unlock()
+	hooks.list = append(hooks.list, registered)
+}
+
+type skipHooksKey struct{}
+
+// SkipHooks makes writes on ctx bypass change hooks, e.g. for bookkeeping
+// updates that should not show up in the audit trail
+func SkipHooks(ctx context.Context) context.Context {
+	return context.WithValue(contextOrBackground(ctx), skipHooksKey{}, true)
+}
+
+// watchers returns the hooks interested in writes to model on ctx
+func watchers(ctx context.Context, model mgm.Model) []ChangeHook {
+	if skip, _ := ctx.Value(skipHooksKey{}).(bool); skip {
+		return nil
+	}
+
+	collection := mgm.CollName(model)
+	hooks.RLock()
+	defer hooks.RUnlock()
+
+	var matched []ChangeHook
+	for _, registered := range hooks.list {
+		if registered.collections == nil || registered.collections[collection] {
+			matched = append(matched, registered.hook)
+		}
+	}
+	return matched
+}
+
+// tracker captures documents around one write for the hooks watching it
+type tracker struct {
+	ctx        context.Context
+	model      mgm.Model
+	hooks      []ChangeHook
+	collection string
+	before     []bson.M
+}
+
+// track prepares to report a write to model; it returns nil when no hook
+// watches the collection. When filter is not nil the documents it matches
+// (one unless many) are loaded as the before state.
+func track(ctx context.Context, model mgm.Model, filter interface{}, many bool) *tracker {
+	ctx = contextOrBackground(ctx)
+	matched := watchers(ctx, model)
+	if len(matched) == 0 {
+		return nil
+	}
+
+	t := &tracker{ctx: ctx, model: model, hooks: matched, collection: mgm.CollName(model)}
+	if filter != nil {
+		t.before = t.load(filter, many)
+	}
+	return t
+}
+
+// load reads the documents matching filter, ignoring errors
+func (t *tracker) load(filter interface{}, many bool) []bson.M {
+	var docs []bson.M
+	if many {
+		cursor, err := mgm.Coll(t.model).Find(t.ctx, filter)
+		if err != nil {
+			return nil
+		}
+		if err := cursor.All(t.ctx, &docs); err != nil {
+			return nil
+		}
+		return docs
+	}
+
+	doc := bson.M{}
+	if err := mgm.Coll(t.model).FindOne(t.ctx, filter).Decode(&doc); err != nil {
+		return nil
+	}
+	return []bson.M{doc}
+}
+
+// ids lists the _id of the before documents
+func (t *tracker) ids() bson.A {
+	ids := make(bson.A, 0, len(t.before))
+	for _, doc := range t.before {
+		ids = append(ids, doc["_id"])
+	}
+	return ids
+}
+
+// reloaded reports each before document with its current state
+func (t *tracker) reloaded(action Action) {
+	if t == nil || len(t.before) == 0 {
+		return
+	}
+
+	after := map[interface{}]bson.M{}
+	for _, doc := range t.load(bson.M{"_id": bson.M{"$in": t.ids()}}, true) {
+		after[doc["_id"]] = doc
+	}
+	for _, doc := range t.before {
+		t.emit(Change{Action: action, DocumentID: doc["_id"], Before: doc, After: after[doc["_id"]]})
+	}
+}
+
+// removed reports each before document as hard-deleted
+func (t *tracker) removed() {
+	if t == nil {
+		return
+	}
+	for _, doc := range t.before {
+		t.emit(Change{Action: ActionDelete, DocumentID: doc["_id"], Before: doc})
+	}
+}
+
+// written reports model itself as the after state
+func (t *tracker) written(action Action, model mgm.Model) {
+	if t == nil {
+		return
+	}
+
+	after := toDocument(model)
+	var before bson.M
+	if len(t.before) > 0 {
+		before = t.before[0]
+	}
+	t.emit(Change{Action: action, DocumentID: model.GetID(), Before: before, After: after})
+}
+
+func (t *tracker) emit(change Change) {
+	change.Collection = t.collection
+	for _, hook := range t.hooks {
+		hook(t.ctx, change)
+	}
+}
+
+// toDocument converts a model to the bson.M stored for it
+func toDocument(model interface{}) bson.M {
+	data, err := bson.Marshal(model)
+	if err != nil {
+		return nil
+	}
+	doc := bson.M{}
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		return nil
+	}
+	return doc
+}
@@ -1,88 +1,254 @@
 package querybuilder
 
-import "go.mongodb.org/mongo-driver/bson"
+import (
+	"regexp"
+	"strings"
 
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Builder composes a MongoDB filter. Conditions keep the order they were
+// added in, and operators on the same field are merged, so
+// New().Gte("age", 18).Lte("age", 65) builds {age: {$gte: 18, $lte: 65}}.
+// Fields may be dotted paths such as "address.city".
 type Builder struct {
-	filter bson.M
+	entries bson.D
 }
 
+// operators marks a field entry holding query operators rather than an equality value
+type operators bson.D
+
 func New() *Builder {
-	return &Builder{filter: bson.M{}}
+	return &Builder{entries: bson.D{}}
 }
 
 // Eq adds an equality filter
 func (b *Builder) Eq(key string, value any) *Builder {
-	b.filter[key] = value
+	if i := b.index(key); i >= 0 {
+		if _, ok := b.entries[i].Value.(operators); ok {
+			return b.Op(key, "$eq", value)
+		}
+		b.entries[i].Value = value
+		return b
+	}
+	b.entries = append(b.entries, bson.E{Key: key, Value: value})
 	return b
 }
 
 // Ne adds a not equal filter
 func (b *Builder) Ne(key string, value any) *Builder {
-	b.filter[key] = bson.M{"$ne": value}
-	return b
+	return b.Op(key, "$ne", value)
 }
 
 // Gt adds a greater than filter
 func (b *Builder) Gt(key string, value any) *Builder {
-	b.filter[key] = bson.M{"$gt": value}
-	return b
+	return b.Op(key, "$gt", value)
 }
 
 // Gte adds a greater than or equal filter
 func (b *Builder) Gte(key string, value any) *Builder {
-	b.filter[key] = bson.M{"$gte": value}
-	return b
+	return b.Op(key, "$gte", value)
 }
 
 // Lt adds a less than filter
 func (b *Builder) Lt(key string, value any) *Builder {
-	b.filter[key] = bson.M{"$lt": value}
-	return b
+	return b.Op(key, "$lt", value)
 }
 
 // Lte adds a less than or equal filter
 func (b *Builder) Lte(key string, value any) *Builder {
-	b.filter[key] = bson.M{"$lte": value}
-	return b
+	return b.Op(key, "$lte", value)
 }
 
 // In adds an $in operator filter
 func (b *Builder) In(key string, values []any) *Builder {
-	b.filter[key] = bson.M{"$in": values}
-	return b
+	return b.Op(key, "$in", values)
 }
 
 // Nin adds a not in filter
 func (b *Builder) Nin(key string, values []any) *Builder {
-	b.filter[key] = bson.M{"$nin": values}
-	return b
+	return b.Op(key, "$nin", values)
 }
 
-// Regex adds a regex filter with case-insensitive option
-func (b *Builder) Regex(key string, value string) *Builder {
-	b.filter[key] = bson.M{"$regex": value, "$options": "i"}
+// Regex adds a regex filter with case-insensitive option. The pattern is
+// used as is; never pass user input, use Contains or Prefix instead.
+// An empty pattern adds no filter.
+func (b *Builder) Regex(key string, pattern string) *Builder {
+	if pattern == "" {
+		return b
+	}
+	return b.Op(key, "$regex", pattern).Op(key, "$options", "i")
+}
+
+// Contains matches values containing search as literal text, ignoring case.
+// Regex metacharacters in search are escaped; a blank search adds no filter.
+func (b *Builder) Contains(key string, search string) *Builder {
+	search = strings.TrimSpace(search)
+	if search == "" {
+		return b
+	}
+	return b.Regex(key, regexp.QuoteMeta(search))
+}
+
+// Prefix matches values starting with search as literal text, ignoring case.
+// A blank search adds no filter.
+func (b *Builder) Prefix(key string, search string) *Builder {
+	search = strings.TrimSpace(search)
+	if search == "" {
+		return b
+	}
+	return b.Regex(key, "^"+regexp.QuoteMeta(search))
+}
+
+// Text adds a $text search, which needs a text index on the collection.
+// Sort by relevance with FindOptions.TextScore. A blank search adds no filter.
+func (b *Builder) Text(search string) *Builder {
+	search = strings.TrimSpace(search)
+	if search == "" {
+		return b
+	}
+	if i := b.index("$text"); i >= 0 {
+		b.entries[i].Value = bson.D{{Key: "$search", Value: search}}
+		return b
+	}
+	b.entries = append(b.entries, bson.E{Key: "$text", Value: bson.D{{Key: "$search", Value: search}}})
 	return b
 }
 
 // Exists adds a field existence check
 func (b *Builder) Exists(key string, exists bool) *Builder {
-	b.filter[key] = bson.M{"$exists": exists}
-	return b
+	return b.Op(key, "$exists", exists)
+}
+
+// All matches arrays containing every value
+func (b *Builder) All(key string, values []any) *Builder {
+	return b.Op(key, "$all", values)
+}
+
+// Size matches arrays with exactly size elements
+func (b *Builder) Size(key string, size int) *Builder {
+	return b.Op(key, "$size", size)
+}
+
+// Type matches values of any of the BSON types, given by alias ("string") or number
+func (b *Builder) Type(key string, types ...any) *Builder {
+	if len(types) == 1 {
+		return b.Op(key, "$type", types[0])
+	}
+	return b.Op(key, "$type", types)
+}
+
+// Mod matches numbers whose remainder when divided by divisor is remainder
+func (b *Builder) Mod(key string, divisor, remainder int64) *Builder {
+	return b.Op(key, "$mod", bson.A{divisor, remainder})
 }
 
-// Or combines multiple filters with OR logic
-func (b *Builder) Or(filters ...bson.M) *Builder {
-	b.filter["$or"] = filters
+// ElemMatch matches arrays with at least one element satisfying sub.
+// For arrays of scalars, build sub on the empty field:
+// ElemMatch("scores", New().Gte("", 80).Lt("", 90)).
+func (b *Builder) ElemMatch(key string, sub *Builder) *Builder {
+	cond := sub.Build()
+	if len(cond) == 1 && cond[0].Key == "" {
+		return b.Op(key, "$elemMatch", cond[0].Value)
+	}
+	return b.Op(key, "$elemMatch", cond)
+}
+
+// Op adds any query operator to a field, e.g. Op("loc", "$near", point).
+// Repeating an operator on the same field replaces its value.
+func (b *Builder) Op(key string, operator string, value any) *Builder {
+	i := b.index(key)
+	if i < 0 {
+		b.entries = append(b.entries, bson.E{Key: key, Value: operators{{Key: operator, Value: value}}})
+		return b
+	}
+
+	ops, ok := b.entries[i].Value.(operators)
+	if !ok {
+		// An earlier Eq becomes an explicit $eq next to the new operator
+		ops = operators{{Key: "$eq", Value: b.entries[i].Value}}
+	}
+	for j := range ops {
+		if ops[j].Key == operator {
+			ops[j].Value = value
+			b.entries[i].Value = ops
+			return b
+		}
+	}
+	b.entries[i].Value = append(ops, bson.E{Key: operator, Value: value})
 	return b
 }
 
-// And combines multiple filters with AND logic
-func (b *Builder) And(filters ...bson.M) *Builder {
-	b.filter["$and"] = filters
+// Or matches documents satisfying any clause. Clauses are sub-builders or
+// filter documents (bson.M, bson.D). Each call adds a separate group that
+// must hold alongside earlier ones: Or(a, b).Or(c, d) is (a|b) & (c|d).
+func (b *Builder) Or(clauses ...any) *Builder {
+	if b.index("$or") < 0 {
+		b.entries = append(b.entries, bson.E{Key: "$or", Value: buildClauses(clauses)})
+		return b
+	}
+	return b.And(bson.D{{Key: "$or", Value: buildClauses(clauses)}})
+}
+
+// And matches documents satisfying every clause
+func (b *Builder) And(clauses ...any) *Builder {
+	return b.appendClauses("$and", clauses)
+}
+
+// Nor matches documents satisfying none of the clauses
+func (b *Builder) Nor(clauses ...any) *Builder {
+	return b.appendClauses("$nor", clauses)
+}
+
+// Not matches documents that do not satisfy clause
+func (b *Builder) Not(clause any) *Builder {
+	return b.Nor(clause)
+}
+
+// Build returns the filter as an ordered bson.D
+func (b *Builder) Build() bson.D {
+	out := make(bson.D, 0, len(b.entries))
+	for _, e := range b.entries {
+		if ops, ok := e.Value.(operators); ok {
+			e.Value = append(bson.D{}, ops...)
+		} else if clauses, ok := e.Value.(bson.A); ok {
+			e.Value = append(bson.A{}, clauses...)
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// index returns the position of key in the filter, or -1
+func (b *Builder) index(key string) int {
+	for i, e := range b.entries {
+		if e.Key == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// appendClauses adds clauses to a top-level $and or $nor list
+func (b *Builder) appendClauses(operator string, clauses []any) *Builder {
+	built := buildClauses(clauses)
+	if i := b.index(operator); i >= 0 {
+		b.entries[i].Value = append(b.entries[i].Value.(bson.A), built...)
+		return b
+	}
+	b.entries = append(b.entries, bson.E{Key: operator, Value: built})
 	return b
 }
 
-// Build returns the final bson.M filter
-func (b *Builder) Build() bson.M {
-	return b.filter
+// buildClauses builds sub-builders and keeps filter documents as they are
+func buildClauses(clauses []any) bson.A {
+	built := make(bson.A, 0, len(clauses))
+	for _, clause := range clauses {
+		if sub, ok := clause.(*Builder); ok {
+			built = append(built, sub.Build())
+			continue
+		}
+		built = append(built, clause)
+	}
+	return built
 }
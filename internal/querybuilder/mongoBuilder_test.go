@@ -0,0 +1,184 @@
+package querybuilder
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestBuilder(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func() *Builder
+		want  bson.D
+	}{
+		{
+			name:  "empty",
+			build: New,
+			want:  bson.D{},
+		},
+		{
+			name:  "operators on one field are merged",
+			build: func() *Builder { return New().Gte("age", 18).Lte("age", 65) },
+			want:  bson.D{{Key: "age", Value: bson.D{{Key: "$gte", Value: 18}, {Key: "$lte", Value: 65}}}},
+		},
+		{
+			name:  "repeated operator replaces its value",
+			build: func() *Builder { return New().Gt("age", 18).Gt("age", 21) },
+			want:  bson.D{{Key: "age", Value: bson.D{{Key: "$gt", Value: 21}}}},
+		},
+		{
+			name:  "repeated Eq replaces the value",
+			build: func() *Builder { return New().Eq("status", "active").Eq("status", "blocked") },
+			want:  bson.D{{Key: "status", Value: "blocked"}},
+		},
+		{
+			name:  "Eq followed by an operator becomes $eq",
+			build: func() *Builder { return New().Eq("age", 30).Ne("age", 40) },
+			want:  bson.D{{Key: "age", Value: bson.D{{Key: "$eq", Value: 30}, {Key: "$ne", Value: 40}}}},
+		},
+		{
+			name:  "Eq after an operator adds $eq",
+			build: func() *Builder { return New().Lt("age", 65).Eq("age", 30) },
+			want:  bson.D{{Key: "age", Value: bson.D{{Key: "$lt", Value: 65}, {Key: "$eq", Value: 30}}}},
+		},
+		{
+			name:  "fields keep insertion order",
+			build: func() *Builder { return New().Eq("b", 1).Eq("a", 2).Eq("c", 3) },
+			want:  bson.D{{Key: "b", Value: 1}, {Key: "a", Value: 2}, {Key: "c", Value: 3}},
+		},
+		{
+			name:  "Contains escapes regex metacharacters",
+			build: func() *Builder { return New().Contains("name", " a.b* ") },
+			want:  bson.D{{Key: "name", Value: bson.D{{Key: "$regex", Value: `a\.b\*`}, {Key: "$options", Value: "i"}}}},
+		},
+		{
+			name:  "Prefix anchors the escaped search",
+			build: func() *Builder { return New().Prefix("name", "(jo") },
+			want:  bson.D{{Key: "name", Value: bson.D{{Key: "$regex", Value: `^\(jo`}, {Key: "$options", Value: "i"}}}},
+		},
+		{
+			name:  "blank searches add nothing",
+			build: func() *Builder { return New().Contains("name", "  ").Prefix("name", "").Text(" ").Regex("name", "") },
+			want:  bson.D{},
+		},
+		{
+			name:  "single Or",
+			build: func() *Builder { return New().Or(New().Eq("a", 1), bson.M{"b": 2}) },
+			want:  bson.D{{Key: "$or", Value: bson.A{bson.D{{Key: "a", Value: 1}}, bson.M{"b": 2}}}},
+		},
+		{
+			name: "second Or is grouped under $and",
+			build: func() *Builder {
+				return New().Or(New().Eq("a", 1), New().Eq("b", 2)).Or(New().Eq("c", 3), New().Eq("d", 4))
+			},
+			want: bson.D{
+				{Key: "$or", Value: bson.A{bson.D{{Key: "a", Value: 1}}, bson.D{{Key: "b", Value: 2}}}},
+				{Key: "$and", Value: bson.A{
+					bson.D{{Key: "$or", Value: bson.A{bson.D{{Key: "c", Value: 3}}, bson.D{{Key: "d", Value: 4}}}}},
+				}},
+			},
+		},
+		{
+			name:  "And calls append to one list",
+			build: func() *Builder { return New().And(bson.M{"a": 1}).And(bson.M{"b": 2}) },
+			want:  bson.D{{Key: "$and", Value: bson.A{bson.M{"a": 1}, bson.M{"b": 2}}}},
+		},
+		{
+			name:  "Not adds a $nor clause",
+			build: func() *Builder { return New().Not(New().Eq("deleted", true)) },
+			want:  bson.D{{Key: "$nor", Value: bson.A{bson.D{{Key: "deleted", Value: true}}}}},
+		},
+		{
+			name:  "ElemMatch on documents",
+			build: func() *Builder { return New().ElemMatch("items", New().Eq("sku", "x").Gt("qty", 1)) },
+			want: bson.D{{Key: "items", Value: bson.D{{Key: "$elemMatch", Value: bson.D{
+				{Key: "sku", Value: "x"},
+				{Key: "qty", Value: bson.D{{Key: "$gt", Value: 1}}},
+			}}}}},
+		},
+		{
+			name:  "ElemMatch on scalars",
+			build: func() *Builder { return New().ElemMatch("scores", New().Gte("", 80).Lt("", 90)) },
+			want: bson.D{{Key: "scores", Value: bson.D{{Key: "$elemMatch", Value: bson.D{
+				{Key: "$gte", Value: 80},
+				{Key: "$lt", Value: 90},
+			}}}}},
+		},
+		{
+			name:  "Text replaces an earlier search",
+			build: func() *Builder { return New().Text("old").Text("new") },
+			want:  bson.D{{Key: "$text", Value: bson.D{{Key: "$search", Value: "new"}}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.build().Build(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Build() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuilderBuildCopies(t *testing.T) {
+	b := New().Gte("age", 18).Or(bson.M{"a": 1})
+	built := b.Build()
+	b.Lte("age", 65).Or(bson.M{"b": 2})
+
+	want := bson.D{
+		{Key: "age", Value: bson.D{{Key: "$gte", Value: 18}}},
+		{Key: "$or", Value: bson.A{bson.M{"a": 1}}},
+	}
+	if !reflect.DeepEqual(built, want) {
+		t.Errorf("Build() result changed with the builder: %v, want %v", built, want)
+	}
+}
+
+func TestFindOptionsSort(t *testing.T) {
+	tests := []struct {
+		name string
+		opts FindOptions
+		want interface{}
+	}{
+		{
+			name: "no sort",
+			opts: FindOptions{},
+			want: nil,
+		},
+		{
+			name: "empty bson.M",
+			opts: FindOptions{Sort: bson.M{}},
+			want: nil,
+		},
+		{
+			name: "bson.D keeps its order",
+			opts: FindOptions{Sort: bson.D{{Key: "name", Value: 1}, {Key: "age", Value: -1}}},
+			want: bson.D{{Key: "name", Value: 1}, {Key: "age", Value: -1}},
+		},
+		{
+			name: "bson.M keys are sorted",
+			opts: FindOptions{Sort: bson.M{"name": 1, "age": -1, "created_at": -1}},
+			want: bson.D{{Key: "age", Value: -1}, {Key: "created_at", Value: -1}, {Key: "name", Value: 1}},
+		},
+		{
+			name: "text score comes first",
+			opts: FindOptions{TextScore: true, Sort: bson.M{"name": 1}},
+			want: bson.D{TextScoreSort, {Key: "name", Value: 1}},
+		},
+		{
+			name: "text score alone",
+			opts: FindOptions{TextScore: true},
+			want: bson.D{TextScoreSort},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.opts.sort(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sort() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package querybuilder
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CountMode selects how paginated queries compute their total
+type CountMode int
+
+const (
+	// CountExact counts the matching documents (default)
+	CountExact CountMode = iota
+	// CountEstimated uses the collection's metadata count when nothing is
+	// filtered, which is instant but may be slightly off; filtered queries
+	// fall back to CountExact
+	CountEstimated
+	// CountNone skips counting; Total and TotalPage are -1
+	CountNone
+)
+
+// PageInfo describes where a page sits in the result set
+type PageInfo struct {
+	Total     int64 // -1 when not counted
+	Page      int
+	Limit     int
+	TotalPage int // -1 when not counted
+	HasNext   bool
+	NextPage  int // 0 on the last page
+	PrevPage  int // 0 on the first page
+}
+
+// Counted reports whether Total and TotalPage are known
+func (p PageInfo) Counted() bool {
+	return p.Total >= 0
+}
+
+// newPageInfo builds the page metadata. hasNext comes from fetching one
+// document more than the limit, so it is correct even without a count.
+func newPageInfo(total int64, page, limit int, hasNext bool) PageInfo {
+	info := PageInfo{Total: total, Page: page, Limit: limit, TotalPage: -1, HasNext: hasNext}
+	if total >= 0 {
+		info.TotalPage = int((total + int64(limit) - 1) / int64(limit))
+	}
+	if hasNext {
+		info.NextPage = page + 1
+	}
+	if page > 1 {
+		info.PrevPage = page - 1
+	}
+	return info
+}
+
+// normalizePaginateOptions applies the default and maximum page size
+func normalizePaginateOptions(opts PaginateOptions) PaginateOptions {
+	if opts.Limit <= 0 {
+		opts.Limit = 10
+	}
+	if opts.Limit > 100 {
+		opts.Limit = 100
+	}
+	if opts.Page <= 0 {
+		opts.Page = 1
+	}
+	return opts
+}
+
+// count returns the total for a paginated find according to opts.Count
+func (r *BaseRepository) count(ctx context.Context, model mgm.Model, filter interface{}, opts PaginateOptions) (int64, error) {
+	filter = scope(ctx, model, filter)
+	switch {
+	case opts.Count == CountNone:
+		return -1, nil
+	case opts.Count == CountEstimated && isEmptyFilter(filter):
+		return mgm.Coll(model).EstimatedDocumentCount(ctx)
+	}
+
+	countOpts := options.Count()
+	if opts.Hint != nil {
+		countOpts.SetHint(opts.Hint)
+	}
+	return mgm.Coll(model).CountDocuments(ctx, filter, countOpts)
+}
+
+// trimResults cuts results, a pointer to a slice, to limit elements and
+// reports whether it was longer
+func trimResults(results interface{}, limit int) bool {
+	slice := reflect.ValueOf(results)
+	if slice.Kind() != reflect.Ptr || slice.Elem().Kind() != reflect.Slice {
+		return false
+	}
+	slice = slice.Elem()
+	if slice.Len() <= limit {
+		return false
+	}
+	slice.Set(slice.Slice(0, limit))
+	return true
+}
@@ -0,0 +1,101 @@
+package querybuilder
+
+import "testing"
+
+func TestNewPageInfo(t *testing.T) {
+	tests := []struct {
+		name    string
+		total   int64
+		page    int
+		limit   int
+		hasNext bool
+		want    PageInfo
+	}{
+		{
+			name:  "first of several pages",
+			total: 25, page: 1, limit: 10, hasNext: true,
+			want: PageInfo{Total: 25, Page: 1, Limit: 10, TotalPage: 3, HasNext: true, NextPage: 2},
+		},
+		{
+			name:  "last partial page",
+			total: 25, page: 3, limit: 10,
+			want: PageInfo{Total: 25, Page: 3, Limit: 10, TotalPage: 3, PrevPage: 2},
+		},
+		{
+			name:  "exact multiple of the limit",
+			total: 20, page: 2, limit: 10,
+			want: PageInfo{Total: 20, Page: 2, Limit: 10, TotalPage: 2, PrevPage: 1},
+		},
+		{
+			name:  "no results",
+			total: 0, page: 1, limit: 10,
+			want: PageInfo{Total: 0, Page: 1, Limit: 10, TotalPage: 0},
+		},
+		{
+			name:  "not counted",
+			total: -1, page: 2, limit: 10, hasNext: true,
+			want: PageInfo{Total: -1, Page: 2, Limit: 10, TotalPage: -1, HasNext: true, NextPage: 3, PrevPage: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newPageInfo(tt.total, tt.page, tt.limit, tt.hasNext)
+			if got != tt.want {
+				t.Errorf("newPageInfo() = %+v, want %+v", got, tt.want)
+			}
+			if got.Counted() != (tt.total >= 0) {
+				t.Errorf("Counted() = %v, want %v", got.Counted(), tt.total >= 0)
+			}
+		})
+	}
+}
+
+func TestNormalizePaginateOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      PaginateOptions
+		wantPage  int
+		wantLimit int
+	}{
+		{name: "defaults", opts: PaginateOptions{}, wantPage: 1, wantLimit: 10},
+		{name: "negative values", opts: PaginateOptions{Page: -2, Limit: -5}, wantPage: 1, wantLimit: 10},
+		{name: "limit capped", opts: PaginateOptions{Page: 4, Limit: 500}, wantPage: 4, wantLimit: 100},
+		{name: "kept", opts: PaginateOptions{Page: 2, Limit: 25}, wantPage: 2, wantLimit: 25},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := normalizePaginateOptions(tt.opts)
+			if got.Page != tt.wantPage || got.Limit != tt.wantLimit {
+				t.Errorf("normalizePaginateOptions() page, limit = %d, %d, want %d, %d", got.Page, got.Limit, tt.wantPage, tt.wantLimit)
+			}
+		})
+	}
+}
+
+func TestTrimResults(t *testing.T) {
+	tests := []struct {
+		name    string
+		results []int
+		limit   int
+		want    bool
+		wantLen int
+	}{
+		{name: "one extra", results: []int{1, 2, 3}, limit: 2, want: true, wantLen: 2},
+		{name: "exactly the limit", results: []int{1, 2}, limit: 2, want: false, wantLen: 2},
+		{name: "short page", results: []int{1}, limit: 2, want: false, wantLen: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := tt.results
+			if got := trimResults(&results, tt.limit); got != tt.want {
+				t.Errorf("trimResults() = %v, want %v", got, tt.want)
+			}
+			if len(results) != tt.wantLen {
+				t.Errorf("len(results) = %d, want %d", len(results), tt.wantLen)
+			}
+		})
+	}
+}
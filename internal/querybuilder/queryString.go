@@ -0,0 +1,371 @@
+package querybuilder
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// QuerySpec whitelists what a list endpoint accepts in its query string.
+// Fields are named as in the model's JSON, e.g. "created_at".
+type QuerySpec struct {
+	// Model is the struct whose json/bson tags map query names to
+	// document fields and whose field types drive value coercion
+	Model interface{}
+
+	Filterable []string
+	Sortable   []string
+	Selectable []string
+
+	// DefaultSort applies when the query has no sort, e.g. "-created_at"
+	DefaultSort string
+}
+
+// Query is a parsed query string
+type Query struct {
+	Filter     *Builder
+	Sort       bson.D
+	Projection bson.D
+}
+
+// QueryError describes a rejected query parameter. Reason is one of
+// invalid_syntax, unknown_field, unknown_operator, invalid_value,
+// not_sortable and not_selectable.
+type QueryError struct {
+	Param  string `json:"param"`
+	Reason string `json:"reason"`
+	Value  string `json:"value"`
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("query parameter %s: %s %q", e.Param, e.Reason, e.Value)
+}
+
+// filterParam matches filter[field] and filter[field][operator]
+var filterParam = regexp.MustCompile(`^filter\[([^\[\]]+)\](?:\[([^\[\]]+)\])?$`)
+
+// queryField is a model field addressable from the query string
+type queryField struct {
+	path string
+	typ  reflect.Type
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	objectIDType = reflect.TypeOf(primitive.ObjectID{})
+)
+
+// ParseQuery translates a raw query string into a filter, sort and
+// projection, following this grammar:
+//
+//	filter[name]=x                 equality
+//	filter[name][op]=x             op: eq ne gt gte lt lte in nin contains prefix exists
+//	filter[tags][in]=a,b           in/nin take comma separated values
+//	sort=-created_at,name          "-" for descending
+//	fields=name,email              projection
+//
+// Values are coerced to the model field's type (numbers, booleans,
+// RFC 3339 or YYYY-MM-DD times, ObjectIDs). Fields outside the spec's
+// whitelists and unknown operators are rejected with a *QueryError.
+// Other parameters, such as page or search, are ignored.
+func ParseQuery(raw string, spec QuerySpec) (*Query, error) {
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		return nil, &QueryError{Param: "query", Reason: "invalid_syntax", Value: raw}
+	}
+
+	fields := modelFields(spec.Model)
+	query := &Query{Filter: New()}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := values.Get(key)
+		switch {
+		case key == "sort":
+			if query.Sort, err = parseSort(value, spec, fields); err != nil {
+				return nil, err
+			}
+		case key == "fields":
+			if query.Projection, err = parseFields(value, spec, fields); err != nil {
+				return nil, err
+			}
+		case strings.HasPrefix(key, "filter"):
+			match := filterParam.FindStringSubmatch(key)
+			if match == nil {
+				return nil, &QueryError{Param: key, Reason: "invalid_syntax", Value: key}
+			}
+			if err := applyFilter(query.Filter, key, match[1], match[2], value, spec, fields); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if query.Sort == nil && spec.DefaultSort != "" {
+		if query.Sort, err = parseSort(spec.DefaultSort, QuerySpec{Sortable: allowAll(fields)}, fields); err != nil {
+			return nil, err
+		}
+	}
+	return query, nil
+}
+
+// FindOptions returns the query's sort and projection
+func (q *Query) FindOptions() *FindOptions {
+	opts := &FindOptions{}
+	if len(q.Sort) > 0 {
+		opts.Sort = q.Sort
+	}
+	if len(q.Projection) > 0 {
+		opts.Projection = q.Projection
+	}
+	return opts
+}
+
+// PaginateOptions returns page/limit pagination options with the query's
+// sort and projection
+func (q *Query) PaginateOptions(page, limit int) PaginateOptions {
+	opts := PaginateOptions{Page: page, Limit: limit}
+	if len(q.Sort) > 0 {
+		opts.Sort = q.Sort
+	}
+	if len(q.Projection) > 0 {
+		opts.Projection = q.Projection
+	}
+	return opts
+}
+
+// CursorOptions returns keyset pagination options ordered by the query's
+// first sort field; further sort fields are replaced by the _id tie-breaker
+func (q *Query) CursorOptions(cursor string, limit int) CursorOptions {
+	opts := CursorOptions{Cursor: cursor, Limit: limit, Projection: q.Projection}
+	if len(q.Sort) > 0 {
+		opts.SortField = q.Sort[0].Key
+		opts.Descending = q.Sort[0].Value == -1
+	}
+	return opts
+}
+
+// applyFilter adds one filter[field][operator]=value condition
+func applyFilter(b *Builder, param, name, operator, raw string, spec QuerySpec, fields map[string]queryField) error {
+	field, ok := fields[name]
+	if !ok || !contains(spec.Filterable, name) {
+		return &QueryError{Param: param, Reason: "unknown_field", Value: name}
+	}
+	if operator == "" {
+		operator = "eq"
+	}
+
+	switch operator {
+	case "eq", "ne", "gt", "gte", "lt", "lte":
+		value, err := coerce(raw, field.typ)
+		if err != nil {
+			return &QueryError{Param: param, Reason: "invalid_value", Value: raw}
+		}
+		if operator == "eq" {
+			b.Eq(field.path, value)
+		} else {
+			b.Op(field.path, "$"+operator, value)
+		}
+	case "in", "nin":
+		parts := strings.Split(raw, ",")
+		list := make([]any, 0, len(parts))
+		for _, part := range parts {
+			value, err := coerce(strings.TrimSpace(part), field.typ)
+			if err != nil {
+				return &QueryError{Param: param, Reason: "invalid_value", Value: part}
+			}
+			list = append(list, value)
+		}
+		b.Op(field.path, "$"+operator, list)
+	case "contains", "prefix":
+		if elemType(field.typ).Kind() != reflect.String {
+			return &QueryError{Param: param, Reason: "unknown_operator", Value: operator}
+		}
+		if operator == "contains" {
+			b.Contains(field.path, raw)
+		} else {
+			b.Prefix(field.path, raw)
+		}
+	case "exists":
+		exists, err := strconv.ParseBool(raw)
+		if err != nil {
+			return &QueryError{Param: param, Reason: "invalid_value", Value: raw}
+		}
+		b.Exists(field.path, exists)
+	default:
+		return &QueryError{Param: param, Reason: "unknown_operator", Value: operator}
+	}
+	return nil
+}
+
+// parseSort turns "-created_at,name" into an ordered sort document
+func parseSort(raw string, spec QuerySpec, fields map[string]queryField) (bson.D, error) {
+	sort := bson.D{}
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		direction := 1
+		if strings.HasPrefix(name, "-") {
+			name, direction = name[1:], -1
+		}
+		if name == "" {
+			continue
+		}
+
+		field, ok := fields[name]
+		if !ok || !contains(spec.Sortable, name) {
+			return nil, &QueryError{Param: "sort", Reason: "not_sortable", Value: name}
+		}
+		sort = append(sort, bson.E{Key: field.path, Value: direction})
+	}
+	return sort, nil
+}
+
+// parseFields turns "name,email" into an inclusion projection
+func parseFields(raw string, spec QuerySpec, fields map[string]queryField) (bson.D, error) {
+	projection := bson.D{}
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		field, ok := fields[name]
+		if !ok || !contains(spec.Selectable, name) {
+			return nil, &QueryError{Param: "fields", Reason: "not_selectable", Value: name}
+		}
+		projection = append(projection, bson.E{Key: field.path, Value: 1})
+	}
+	return projection, nil
+}
+
+// coerce parses raw into the BSON value matching a model field type.
+// For array fields the element type is used.
+func coerce(raw string, typ reflect.Type) (interface{}, error) {
+	typ = elemType(typ)
+
+	switch typ {
+	case timeType:
+		if t, err := time.Parse(time.RFC3339, raw); err == nil {
+			return t, nil
+		}
+		return time.Parse(time.DateOnly, raw)
+	case objectIDType:
+		return primitive.ObjectIDFromHex(raw)
+	}
+
+	switch typ.Kind() {
+	case reflect.String:
+		return raw, nil
+	case reflect.Bool:
+		return strconv.ParseBool(raw)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.ParseInt(raw, 10, 64)
+	case reflect.Float32, reflect.Float64:
+		return strconv.ParseFloat(raw, 64)
+	default:
+		return raw, nil
+	}
+}
+
+// elemType unwraps pointers and the element type of slices
+func elemType(typ reflect.Type) reflect.Type {
+	for typ.Kind() == reflect.Ptr || (typ.Kind() == reflect.Slice && typ.Elem().Kind() != reflect.Uint8) {
+		typ = typ.Elem()
+	}
+	return typ
+}
+
+// fieldCache holds the query fields of each model type
+var fieldCache sync.Map
+
+// modelFields maps the JSON names of a model's fields, including inlined
+// and nested structs ("address.city"), to their document paths and types
+func modelFields(model interface{}) map[string]queryField {
+	typ := reflect.TypeOf(model)
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		return map[string]queryField{}
+	}
+	if cached, ok := fieldCache.Load(typ); ok {
+		return cached.(map[string]queryField)
+	}
+
+	fields := map[string]queryField{}
+	collectFields(typ, "", "", fields)
+	fieldCache.Store(typ, fields)
+	return fields
+}
+
+func collectFields(typ reflect.Type, namePrefix, pathPrefix string, fields map[string]queryField) {
+	for i := 0; i < typ.NumField(); i++ {
+		sf := typ.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		bsonName, bsonOpts, _ := strings.Cut(sf.Tag.Get("bson"), ",")
+		jsonName, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if bsonName == "-" || jsonName == "-" {
+			continue
+		}
+
+		fieldType := sf.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if strings.Contains(bsonOpts, "inline") || (sf.Anonymous && bsonName == "") {
+			if fieldType.Kind() == reflect.Struct {
+				collectFields(fieldType, namePrefix, pathPrefix, fields)
+			}
+			continue
+		}
+
+		if bsonName == "" {
+			bsonName = strings.ToLower(sf.Name)
+		}
+		if jsonName == "" {
+			jsonName = sf.Name
+		}
+		name, path := namePrefix+jsonName, pathPrefix+bsonName
+
+		if fieldType.Kind() == reflect.Struct && fieldType != timeType {
+			collectFields(fieldType, name+".", path+".", fields)
+			continue
+		}
+		fields[name] = queryField{path: path, typ: sf.Type}
+	}
+}
+
+// allowAll lists every field name, for the spec's own DefaultSort
+func allowAll(fields map[string]queryField) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	return names
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
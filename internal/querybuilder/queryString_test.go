@@ -0,0 +1,204 @@
+package querybuilder
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type queryAddress struct {
+	City string `json:"city" bson:"city"`
+}
+
+type queryModel struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id"`
+	Name      string             `json:"name" bson:"name"`
+	Age       int                `json:"age" bson:"age"`
+	Score     float64            `json:"score" bson:"score"`
+	Active    bool               `json:"active" bson:"active"`
+	Tags      []string           `json:"tags" bson:"tags"`
+	Address   queryAddress       `json:"address" bson:"address"`
+	CreatedAt time.Time          `json:"created_at" bson:"createdAt"`
+	Secret    string             `json:"-" bson:"secret"`
+}
+
+var querySpec = QuerySpec{
+	Model:       &queryModel{},
+	Filterable:  []string{"id", "name", "age", "score", "active", "tags", "address.city", "created_at"},
+	Sortable:    []string{"name", "age", "created_at"},
+	Selectable:  []string{"name", "age", "address.city"},
+	DefaultSort: "-created_at",
+}
+
+func TestParseQuery(t *testing.T) {
+	id := primitive.NewObjectID()
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		raw            string
+		wantFilter     bson.D
+		wantSort       bson.D
+		wantProjection bson.D
+	}{
+		{
+			name:       "no parameters uses the default sort",
+			raw:        "",
+			wantFilter: bson.D{},
+			wantSort:   bson.D{{Key: "createdAt", Value: -1}},
+		},
+		{
+			name:       "equality is coerced to the field type, in parameter order",
+			raw:        "filter[age]=30&filter[active]=true&filter[id]=" + id.Hex(),
+			wantFilter: bson.D{{Key: "active", Value: true}, {Key: "age", Value: int64(30)}, {Key: "_id", Value: id}},
+			wantSort:   bson.D{{Key: "createdAt", Value: -1}},
+		},
+		{
+			name:       "operators on one field are merged",
+			raw:        "filter[age][gte]=18&filter[age][lt]=65",
+			wantFilter: bson.D{{Key: "age", Value: bson.D{{Key: "$gte", Value: int64(18)}, {Key: "$lt", Value: int64(65)}}}},
+			wantSort:   bson.D{{Key: "createdAt", Value: -1}},
+		},
+		{
+			name:       "in splits and coerces values",
+			raw:        "filter[score][in]=1.5, 2",
+			wantFilter: bson.D{{Key: "score", Value: bson.D{{Key: "$in", Value: []any{1.5, float64(2)}}}}},
+			wantSort:   bson.D{{Key: "createdAt", Value: -1}},
+		},
+		{
+			name:       "array fields use the element type",
+			raw:        "filter[tags][nin]=a,b",
+			wantFilter: bson.D{{Key: "tags", Value: bson.D{{Key: "$nin", Value: []any{"a", "b"}}}}},
+			wantSort:   bson.D{{Key: "createdAt", Value: -1}},
+		},
+		{
+			name:       "nested fields map to document paths",
+			raw:        "filter[address.city][prefix]=new",
+			wantFilter: bson.D{{Key: "address.city", Value: bson.D{{Key: "$regex", Value: "^new"}, {Key: "$options", Value: "i"}}}},
+			wantSort:   bson.D{{Key: "createdAt", Value: -1}},
+		},
+		{
+			name:       "dates accept YYYY-MM-DD",
+			raw:        "filter[created_at][gte]=2024-05-01",
+			wantFilter: bson.D{{Key: "createdAt", Value: bson.D{{Key: "$gte", Value: day}}}},
+			wantSort:   bson.D{{Key: "createdAt", Value: -1}},
+		},
+		{
+			name:       "exists",
+			raw:        "filter[name][exists]=false",
+			wantFilter: bson.D{{Key: "name", Value: bson.D{{Key: "$exists", Value: false}}}},
+			wantSort:   bson.D{{Key: "createdAt", Value: -1}},
+		},
+		{
+			name:           "sort and fields",
+			raw:            "sort=-age,name&fields=name,address.city&page=2",
+			wantFilter:     bson.D{},
+			wantSort:       bson.D{{Key: "age", Value: -1}, {Key: "name", Value: 1}},
+			wantProjection: bson.D{{Key: "name", Value: 1}, {Key: "address.city", Value: 1}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := ParseQuery(tt.raw, querySpec)
+			if err != nil {
+				t.Fatalf("ParseQuery(%q) error = %v", tt.raw, err)
+			}
+			if got := query.Filter.Build(); !reflect.DeepEqual(got, tt.wantFilter) {
+				t.Errorf("filter = %v, want %v", got, tt.wantFilter)
+			}
+			if !reflect.DeepEqual(query.Sort, tt.wantSort) {
+				t.Errorf("sort = %v, want %v", query.Sort, tt.wantSort)
+			}
+			if !reflect.DeepEqual(query.Projection, tt.wantProjection) {
+				t.Errorf("projection = %v, want %v", query.Projection, tt.wantProjection)
+			}
+		})
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want QueryError
+	}{
+		{
+			name: "malformed query string",
+			raw:  "filter[age]=%zz",
+			want: QueryError{Param: "query", Reason: "invalid_syntax", Value: "filter[age]=%zz"},
+		},
+		{
+			name: "malformed filter parameter",
+			raw:  "filter[age=1",
+			want: QueryError{Param: "filter[age", Reason: "invalid_syntax", Value: "filter[age"},
+		},
+		{
+			name: "field not filterable",
+			raw:  "filter[secret]=x",
+			want: QueryError{Param: "filter[secret]", Reason: "unknown_field", Value: "secret"},
+		},
+		{
+			name: "unknown operator",
+			raw:  "filter[age][near]=1",
+			want: QueryError{Param: "filter[age][near]", Reason: "unknown_operator", Value: "near"},
+		},
+		{
+			name: "contains on a number",
+			raw:  "filter[age][contains]=1",
+			want: QueryError{Param: "filter[age][contains]", Reason: "unknown_operator", Value: "contains"},
+		},
+		{
+			name: "number that does not parse",
+			raw:  "filter[age][gt]=old",
+			want: QueryError{Param: "filter[age][gt]", Reason: "invalid_value", Value: "old"},
+		},
+		{
+			name: "bad list element",
+			raw:  "filter[age][in]=1,x",
+			want: QueryError{Param: "filter[age][in]", Reason: "invalid_value", Value: "x"},
+		},
+		{
+			name: "bad ObjectID",
+			raw:  "filter[id]=123",
+			want: QueryError{Param: "filter[id]", Reason: "invalid_value", Value: "123"},
+		},
+		{
+			name: "bad date",
+			raw:  "filter[created_at][lt]=yesterday",
+			want: QueryError{Param: "filter[created_at][lt]", Reason: "invalid_value", Value: "yesterday"},
+		},
+		{
+			name: "bad exists flag",
+			raw:  "filter[name][exists]=maybe",
+			want: QueryError{Param: "filter[name][exists]", Reason: "invalid_value", Value: "maybe"},
+		},
+		{
+			name: "field not sortable",
+			raw:  "sort=-score",
+			want: QueryError{Param: "sort", Reason: "not_sortable", Value: "score"},
+		},
+		{
+			name: "field not selectable",
+			raw:  "fields=name,secret",
+			want: QueryError{Param: "fields", Reason: "not_selectable", Value: "secret"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseQuery(tt.raw, querySpec)
+			var queryErr *QueryError
+			if !errors.As(err, &queryErr) {
+				t.Fatalf("ParseQuery(%q) error = %v, want a *QueryError", tt.raw, err)
+			}
+			if *queryErr != tt.want {
+				t.Errorf("ParseQuery(%q) error = %+v, want %+v", tt.raw, *queryErr, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,278 @@
+package querybuilder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ErrNotFound is matched by every *NotFoundError
+var ErrNotFound = errors.New("document not found")
+
+// NotFoundError reports a missing document. It matches ErrNotFound and
+// mongo.ErrNoDocuments with errors.Is.
+type NotFoundError struct {
+	Collection string
+	ID         string
+}
+
+func (e *NotFoundError) Error() string {
+	if e.ID == "" {
+		return fmt.Sprintf("%s: document not found", e.Collection)
+	}
+	return fmt.Sprintf("%s: document %s not found", e.Collection, e.ID)
+}
+
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound || target == mongo.ErrNoDocuments
+}
+
+// IsNotFound reports whether err means the document does not exist
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound) || errors.Is(err, mongo.ErrNoDocuments)
+}
+
+// Page is a typed page of results
+type Page[T any] struct {
+	Items []T
+	PageInfo
+}
+
+// Repository provides typed CRUD for one MGM model, e.g.
+// querybuilder.NewRepository[*user.User](). IDs are ObjectID hex strings;
+// malformed IDs are reported as not found.
+type Repository[T mgm.Model] struct {
+	base *BaseRepository
+}
+
+// NewRepository creates a typed repository for T, a pointer to an MGM model
+func NewRepository[T mgm.Model]() *Repository[T] {
+	return &Repository[T]{base: NewBaseRepository()}
+}
+
+// Base returns the untyped repository, for aggregations and bulk writes
+func (r *Repository[T]) Base() *BaseRepository {
+	return r.base
+}
+
+// Collection returns the model's collection
+func (r *Repository[T]) Collection() *mgm.Collection {
+	return mgm.Coll(r.model())
+}
+
+// FindByID retrieves a document by its ObjectID hex string
+func (r *Repository[T]) FindByID(ctx context.Context, id string) (T, error) {
+	var zero T
+	objectID, err := r.ObjectID(id)
+	if err != nil {
+		return zero, err
+	}
+
+	model := r.model()
+	if err := r.base.FindById(ctx, model, objectID); err != nil {
+		return zero, r.notFound(err, id)
+	}
+	return model, nil
+}
+
+// FindOne retrieves the first document matching filter
+func (r *Repository[T]) FindOne(ctx context.Context, filter interface{}, opts *FindOptions) (T, error) {
+	var zero T
+	model := r.model()
+	if err := r.base.FindOne(ctx, model, filter, opts); err != nil {
+		return zero, r.notFound(err, "")
+	}
+	return model, nil
+}
+
+// Find retrieves every document matching filter
+func (r *Repository[T]) Find(ctx context.Context, filter interface{}, opts *FindOptions) ([]T, error) {
+	results := []T{}
+	if err := r.base.Find(ctx, r.model(), &results, filter, opts); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// Count counts the documents matching filter
+func (r *Repository[T]) Count(ctx context.Context, filter interface{}) (int64, error) {
+	return r.base.CountDocuments(ctx, r.model(), filter)
+}
+
+// Paginate retrieves one page of the documents matching filter
+func (r *Repository[T]) Paginate(ctx context.Context, filter interface{}, opts PaginateOptions) (*Page[T], error) {
+	results := []T{}
+	result, err := r.base.FindWithPagination(ctx, r.model(), &results, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Page[T]{Items: results, PageInfo: result.PageInfo}, nil
+}
+
+// PaginatePipeline retrieves one page of pipeline's output, decoded as T
+func (r *Repository[T]) PaginatePipeline(ctx context.Context, pipeline mongo.Pipeline, opts PaginateOptions) (*Page[T], error) {
+	results := []T{}
+	info, err := r.base.paginatePipeline(ctx, r.model(), pipeline, &results, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Page[T]{Items: results, PageInfo: info}, nil
+}
+
+// FindWithCursor retrieves one keyset-paginated page of the documents matching filter
+func (r *Repository[T]) FindWithCursor(ctx context.Context, filter interface{}, opts CursorOptions) ([]T, *CursorPage, error) {
+	results := []T{}
+	page, err := r.base.FindWithCursor(ctx, r.model(), &results, filter, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return results, page, nil
+}
+
+// AggregateWithCursor retrieves one keyset-paginated page of pipeline's output
+func (r *Repository[T]) AggregateWithCursor(ctx context.Context, pipeline mongo.Pipeline, opts CursorOptions) ([]T, *CursorPage, error) {
+	results := []T{}
+	page, err := r.base.AggregateWithCursor(ctx, r.model(), pipeline, &results, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return results, page, nil
+}
+
+// Create inserts model, setting its ID and timestamps
+func (r *Repository[T]) Create(ctx context.Context, model T) error {
+	return r.base.Save(ctx, model)
+}
+
+// Update replaces the stored fields of model
+func (r *Repository[T]) Update(ctx context.Context, model T) error {
+	return r.base.UpdateById(ctx, model)
+}
+
+// UpdateByID applies update (e.g. bson.M{"$set": ...}) to a document and
+// returns the updated document
+func (r *Repository[T]) UpdateByID(ctx context.Context, id string, update interface{}) (T, error) {
+	var zero T
+	objectID, err := r.ObjectID(id)
+	if err != nil {
+		return zero, err
+	}
+
+	model := r.model()
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	filter := scope(ctx, model, bson.M{"_id": objectID})
+	t := track(ctx, model, filter, false)
+	if err := r.Collection().FindOneAndUpdate(ctx, filter, bumpVersion(model, update), opts).Decode(model); err != nil {
+		return zero, r.notFound(err, id)
+	}
+	t.reloaded(ActionUpdate)
+	return model, nil
+}
+
+// UpdateByIDWithVersion is UpdateByID for a document read at version; it
+// returns a *ConflictError when the document has changed since
+func (r *Repository[T]) UpdateByIDWithVersion(ctx context.Context, id string, version int64, update interface{}) (T, error) {
+	var zero T
+	objectID, err := r.ObjectID(id)
+	if err != nil {
+		return zero, err
+	}
+
+	model := r.model()
+	ctx = contextOrBackground(ctx)
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	byID := bson.M{"_id": objectID}
+	filter := scope(ctx, model, versionFilter(byID, version))
+	t := track(ctx, model, filter, false)
+	err = r.Collection().FindOneAndUpdate(ctx, filter, bumpVersion(model, update), opts).Decode(model)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		err = r.base.conflict(ctx, model, byID, id, version)
+	}
+	if err != nil {
+		return zero, r.notFound(err, id)
+	}
+	t.reloaded(ActionUpdate)
+	return model, nil
+}
+
+// Delete removes a document by its ObjectID hex string
+func (r *Repository[T]) Delete(ctx context.Context, id string) error {
+	objectID, err := r.ObjectID(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.base.DeleteOne(ctx, r.model(), bson.M{"_id": objectID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return &NotFoundError{Collection: mgm.CollName(r.model()), ID: id}
+	}
+	return nil
+}
+
+// Restore clears the deletion mark of a soft-deleted document
+func (r *Repository[T]) Restore(ctx context.Context, id string) error {
+	objectID, err := r.ObjectID(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.base.Restore(ctx, r.model(), bson.M{"_id": objectID})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return &NotFoundError{Collection: mgm.CollName(r.model()), ID: id}
+	}
+	return nil
+}
+
+// ForceDelete permanently removes a document, soft-deleted or not
+func (r *Repository[T]) ForceDelete(ctx context.Context, id string) error {
+	objectID, err := r.ObjectID(id)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.base.ForceDelete(ctx, r.model(), bson.M{"_id": objectID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return &NotFoundError{Collection: mgm.CollName(r.model()), ID: id}
+	}
+	return nil
+}
+
+// ObjectID parses an ID, reporting malformed IDs as not found
+func (r *Repository[T]) ObjectID(id string) (primitive.ObjectID, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return primitive.NilObjectID, &NotFoundError{Collection: mgm.CollName(r.model()), ID: id}
+	}
+	return objectID, nil
+}
+
+// model returns a new zero value of the model T points to
+func (r *Repository[T]) model() T {
+	var zero T
+	return reflect.New(reflect.TypeOf(zero).Elem()).Interface().(T)
+}
+
+// notFound turns mongo.ErrNoDocuments into a *NotFoundError
+func (r *Repository[T]) notFound(err error, id string) error {
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return &NotFoundError{Collection: mgm.CollName(r.model()), ID: id}
+	}
+	return err
+}
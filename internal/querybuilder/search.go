@@ -0,0 +1,69 @@
+package querybuilder
+
+import (
+	"context"
+	"sync"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// textIndexed caches the collections known to have a text index
+var textIndexed sync.Map
+
+// TextScoreSort sorts $text results by relevance
+var TextScoreSort = bson.E{Key: "score", Value: bson.M{"$meta": "textScore"}}
+
+// HasTextIndex reports whether the model's collection has a text index.
+// Only a found index is cached, so one created later is picked up on the
+// next call.
+func HasTextIndex(ctx context.Context, model mgm.Model) bool {
+	coll := mgm.Coll(model)
+	if _, ok := textIndexed.Load(coll.Name()); ok {
+		return true
+	}
+
+	cursor, err := coll.Indexes().List(ctx)
+	if err != nil {
+		return false
+	}
+	defer cursor.Close(ctx)
+
+	var indexes []struct {
+		Key bson.D `bson:"key"`
+	}
+	if err := cursor.All(ctx, &indexes); err != nil {
+		return false
+	}
+
+	for _, index := range indexes {
+		for _, key := range index.Key {
+			if key.Value == "text" {
+				textIndexed.Store(coll.Name(), true)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SearchStage returns an Atlas Search $search stage matching query in paths
+// using the named search index. It must be the first stage of a pipeline.
+func SearchStage(index, query string, paths ...string) bson.D {
+	var path interface{} = paths
+	if len(paths) == 1 {
+		path = paths[0]
+	}
+	return bson.D{{Key: "$search", Value: bson.D{
+		{Key: "index", Value: index},
+		{Key: "text", Value: bson.D{
+			{Key: "query", Value: query},
+			{Key: "path", Value: path},
+		}},
+	}}}
+}
+
+// SearchScoreStage adds the Atlas Search relevance as a score field
+func SearchScoreStage() bson.D {
+	return bson.D{{Key: "$addFields", Value: bson.D{{Key: "score", Value: bson.M{"$meta": "searchScore"}}}}}
+}
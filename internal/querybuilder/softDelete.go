@@ -0,0 +1,232 @@
+package querybuilder
+
+import (
+	"context"
+	"time"
+
+	"github.com/addixit1/fiber-boilerplate/internal/lib/reqctx"
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// SoftDeleteModel opts a model into soft deletion when embedded inline:
+//
+//	type User struct {
+//		mgm.DefaultModel           `bson:",inline"`
+//		querybuilder.SoftDeleteModel `bson:",inline"`
+//	}
+//
+// BaseRepository deletes then only mark documents, and reads, counts and
+// updates skip marked documents unless the context says otherwise.
+type SoftDeleteModel struct {
+	DeletedAt *time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+	DeletedBy string     `json:"deleted_by,omitempty" bson:"deleted_by,omitempty"`
+}
+
+// IsDeleted reports whether the document is soft-deleted
+func (m *SoftDeleteModel) IsDeleted() bool {
+	return m.DeletedAt != nil
+}
+
+func (m *SoftDeleteModel) markDeleted(at *time.Time, by string) {
+	m.DeletedAt, m.DeletedBy = at, by
+}
+
+// SoftDeletable is implemented by models embedding SoftDeleteModel
+type SoftDeletable interface {
+	IsDeleted() bool
+	markDeleted(at *time.Time, by string)
+}
+
+// IsSoftDeletable reports whether model embeds SoftDeleteModel
+func IsSoftDeletable(model mgm.Model) bool {
+	_, ok := model.(SoftDeletable)
+	return ok
+}
+
+// deletedMode selects which documents of soft-deletable models are visible
+type deletedMode int
+
+const (
+	withoutDeleted deletedMode = iota
+	withDeleted
+	onlyDeleted
+)
+
+type deletedModeKey struct{}
+
+// WithDeleted makes repository calls on ctx include soft-deleted documents
+func WithDeleted(ctx context.Context) context.Context {
+	return context.WithValue(contextOrBackground(ctx), deletedModeKey{}, withDeleted)
+}
+
+// OnlyDeleted makes repository calls on ctx see soft-deleted documents only
+func OnlyDeleted(ctx context.Context) context.Context {
+	return context.WithValue(contextOrBackground(ctx), deletedModeKey{}, onlyDeleted)
+}
+
+func deletedModeOf(ctx context.Context) deletedMode {
+	if ctx == nil {
+		return withoutDeleted
+	}
+	mode, _ := ctx.Value(deletedModeKey{}).(deletedMode)
+	return mode
+}
+
+// scope restricts filter to the documents visible under ctx's deleted mode
+func scope(ctx context.Context, model mgm.Model, filter interface{}) interface{} {
+	if !IsSoftDeletable(model) {
+		if filter == nil {
+			return bson.D{}
+		}
+		return filter
+	}
+
+	var condition bson.D
+	switch deletedModeOf(ctx) {
+	case withDeleted:
+		if filter == nil {
+			return bson.D{}
+		}
+		return filter
+	case onlyDeleted:
+		condition = bson.D{{Key: "deleted_at", Value: bson.D{{Key: "$ne", Value: nil}}}}
+	default:
+		condition = bson.D{{Key: "deleted_at", Value: nil}}
+	}
+
+	if filter == nil || isEmptyFilter(filter) {
+		return condition
+	}
+	return bson.D{{Key: "$and", Value: bson.A{filter, condition}}}
+}
+
+// scopePipeline adds scope's condition as a $match stage, after a leading
+// stage that must come first ($search, $vectorSearch, $geoNear)
+func scopePipeline(ctx context.Context, model mgm.Model, pipeline mongo.Pipeline) mongo.Pipeline {
+	if !IsSoftDeletable(model) || deletedModeOf(ctx) == withDeleted {
+		return pipeline
+	}
+
+	match := bson.D{{Key: "$match", Value: scope(ctx, model, nil)}}
+	at := 0
+	if len(pipeline) > 0 && len(pipeline[0]) > 0 {
+		switch pipeline[0][0].Key {
+		case "$search", "$searchMeta", "$vectorSearch", "$geoNear":
+			at = 1
+		}
+	}
+
+	scoped := make(mongo.Pipeline, 0, len(pipeline)+1)
+	scoped = append(scoped, pipeline[:at]...)
+	scoped = append(scoped, match)
+	return append(scoped, pipeline[at:]...)
+}
+
+// softDelete marks the documents matching filter as deleted by the
+// request principal on ctx, if any
+func (r *BaseRepository) softDelete(ctx context.Context, model mgm.Model, filter interface{}, many bool) (*mongo.DeleteResult, error) {
+	update := bson.M{"$set": bson.M{"deleted_at": time.Now(), "deleted_by": actorOf(ctx)}}
+	filter = scope(ctx, model, filter)
+	t := track(ctx, model, filter, many)
+
+	var result *mongo.UpdateResult
+	var err error
+	if many {
+		result, err = mgm.Coll(model).UpdateMany(ctx, filter, bumpVersion(model, update))
+	} else {
+		result, err = mgm.Coll(model).UpdateOne(ctx, filter, bumpVersion(model, update))
+	}
+	if err != nil {
+		return nil, err
+	}
+	t.reloaded(ActionDelete)
+	return &mongo.DeleteResult{DeletedCount: result.ModifiedCount}, nil
+}
+
+// Restore clears the deletion mark of the soft-deleted documents matching filter
+func (r *BaseRepository) Restore(ctx context.Context, model mgm.Model, filter interface{}) (*mongo.UpdateResult, error) {
+	ctx = OnlyDeleted(ctx)
+	update := bson.M{
+		"$unset": bson.M{"deleted_at": "", "deleted_by": ""},
+		"$set":   bson.M{"updated_at": time.Now()},
+	}
+	filter = scope(ctx, model, filter)
+	t := track(ctx, model, filter, true)
+	result, err := mgm.Coll(model).UpdateMany(ctx, filter, bumpVersion(model, update))
+	if err != nil {
+		return nil, err
+	}
+	t.reloaded(ActionRestore)
+	return result, nil
+}
+
+// ForceDelete permanently removes the documents matching filter, soft-deleted or not
+func (r *BaseRepository) ForceDelete(ctx context.Context, model mgm.Model, filter interface{}) (*mongo.DeleteResult, error) {
+	ctx = contextOrBackground(ctx)
+	if filter == nil {
+		filter = bson.D{}
+	}
+
+	t := track(ctx, model, filter, true)
+	result, err := mgm.Coll(model).DeleteMany(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	t.removed()
+	return result, nil
+}
+
+// Purge permanently removes documents soft-deleted more than retention ago
+func (r *BaseRepository) Purge(ctx context.Context, model mgm.Model, retention time.Duration) (int64, error) {
+	filter := bson.M{"deleted_at": bson.M{"$lt": time.Now().Add(-retention)}}
+	result, err := r.ForceDelete(ctx, model, filter)
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+// SchedulePurge purges the soft-deleted documents of models every interval
+// until ctx is done. retention is read before each run so it may change.
+// Purges are idempotent, so running it on every instance is safe.
+func SchedulePurge(ctx context.Context, interval time.Duration, retention func() time.Duration, report func(collection string, purged int64, err error), models ...mgm.Model) {
+	if interval <= 0 || len(models) == 0 {
+		return
+	}
+
+	repo := NewBaseRepository()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				for _, model := range models {
+					purged, err := repo.Purge(ctx, model, retention())
+					report(mgm.CollName(model), purged, err)
+				}
+			}
+		}
+	}()
+}
+
+// actorOf identifies the request principal on ctx for deleted_by
+func actorOf(ctx context.Context) string {
+	rc, ok := reqctx.FromContext(contextOrBackground(ctx))
+	if !ok || rc.Principal == nil {
+		return ""
+	}
+	return rc.Principal.ID
+}
+
+func contextOrBackground(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+	return ctx
+}
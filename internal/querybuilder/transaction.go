@@ -0,0 +1,176 @@
+package querybuilder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readconcern"
+	"go.mongodb.org/mongo-driver/mongo/writeconcern"
+)
+
+// ErrTransactionsUnsupported is returned when the server is a standalone
+// mongod; transactions need a replica set (a single node is enough) or mongos
+var ErrTransactionsUnsupported = errors.New("transactions need a replica set or sharded cluster")
+
+// TxOptions tunes RunInTransaction. Zero values use the defaults.
+type TxOptions struct {
+	// MaxRetries bounds retries of the whole transaction after a
+	// TransientTransactionError and of the commit after an
+	// UnknownTransactionCommitResult (default 3)
+	MaxRetries int
+	// Backoff is the delay before the first retry, growing linearly (default 100ms)
+	Backoff time.Duration
+	// Transaction overrides the snapshot read and majority write concerns
+	Transaction *options.TransactionOptions
+	// AllowStandalone runs fn without a transaction on a standalone mongod,
+	// e.g. in local development, instead of failing with ErrTransactionsUnsupported
+	AllowStandalone bool
+}
+
+// standalone caches whether the deployment lacks transactions, once known
+var standalone atomic.Pointer[bool]
+
+// RunInTransaction runs fn in a multi-document transaction and commits it
+// when fn returns nil. fn must do all its reads and writes with the ctx it
+// is given; BaseRepository and Repository methods called with that ctx
+// join the transaction. fn may run more than once, so it must not have
+// side effects outside the database. Calls made inside a transaction reuse
+// it instead of nesting.
+func RunInTransaction(ctx context.Context, fn func(ctx context.Context) error, opts ...TxOptions) error {
+	ctx = contextOrBackground(ctx)
+	if InTransaction(ctx) {
+		return fn(ctx)
+	}
+
+	o := txOptions(opts)
+	if o.AllowStandalone {
+		isStandalone, err := isStandalone(ctx)
+		if err != nil {
+			return err
+		}
+		if isStandalone {
+			return fn(ctx)
+		}
+	}
+
+	_, client, _, err := mgm.DefaultConfigs()
+	if err != nil {
+		return err
+	}
+	session, err := client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(context.WithoutCancel(ctx))
+
+	for attempt := 1; ; attempt++ {
+		err = mongo.WithSession(ctx, session, func(sc mongo.SessionContext) error {
+			if err := sc.StartTransaction(o.Transaction); err != nil {
+				return err
+			}
+			if err := fn(sc); err != nil {
+				_ = sc.AbortTransaction(context.WithoutCancel(sc))
+				return err
+			}
+			return commit(sc, o.MaxRetries)
+		})
+
+		if err == nil || !hasErrorLabel(err, "TransientTransactionError") || attempt > o.MaxRetries {
+			return transactionError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(o.Backoff * time.Duration(attempt)):
+		}
+	}
+}
+
+// InTransaction reports whether ctx carries a session, as the ctx given to
+// a RunInTransaction callback does
+func InTransaction(ctx context.Context) bool {
+	return ctx != nil && mongo.SessionFromContext(ctx) != nil
+}
+
+// SupportsTransactions reports whether the connected deployment is a
+// replica set or sharded cluster
+func SupportsTransactions(ctx context.Context) (bool, error) {
+	_, _, db, err := mgm.DefaultConfigs()
+	if err != nil {
+		return false, err
+	}
+
+	var hello struct {
+		SetName string `bson:"setName"`
+		Msg     string `bson:"msg"`
+	}
+	if err := db.RunCommand(contextOrBackground(ctx), bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		return false, err
+	}
+	return hello.SetName != "" || hello.Msg == "isdbgrid", nil
+}
+
+// isStandalone is !SupportsTransactions, asked once per process
+func isStandalone(ctx context.Context) (bool, error) {
+	if known := standalone.Load(); known != nil {
+		return *known, nil
+	}
+	supported, err := SupportsTransactions(ctx)
+	if err != nil {
+		return false, err
+	}
+	result := !supported
+	standalone.Store(&result)
+	return result, nil
+}
+
+// commit commits the session's transaction, retrying when the outcome is unknown
+func commit(sc mongo.SessionContext, maxRetries int) error {
+	for attempt := 1; ; attempt++ {
+		err := sc.CommitTransaction(sc)
+		if err == nil || !hasErrorLabel(err, "UnknownTransactionCommitResult") || attempt > maxRetries {
+			return err
+		}
+	}
+}
+
+func hasErrorLabel(err error, label string) bool {
+	var serverErr mongo.ServerError
+	return errors.As(err, &serverErr) && serverErr.HasErrorLabel(label)
+}
+
+// transactionError explains the IllegalOperation error of standalone servers
+func transactionError(err error) error {
+	var serverErr mongo.ServerError
+	if errors.As(err, &serverErr) && serverErr.HasErrorCode(20) {
+		return fmt.Errorf("%w: %v", ErrTransactionsUnsupported, err)
+	}
+	return err
+}
+
+func txOptions(opts []TxOptions) TxOptions {
+	var o TxOptions
+	if len(opts) > 0 {
+		o = opts[0]
+	}
+	if o.MaxRetries <= 0 {
+		o.MaxRetries = 3
+	}
+	if o.Backoff <= 0 {
+		o.Backoff = 100 * time.Millisecond
+	}
+	if o.Transaction == nil {
+		o.Transaction = options.Transaction().
+			SetReadConcern(readconcern.Snapshot()).
+			SetWriteConcern(writeconcern.Majority())
+	}
+	return o
+}
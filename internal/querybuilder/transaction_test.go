@@ -0,0 +1,139 @@
+package querybuilder
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// The transaction tests need a replica set and are skipped without one:
+//
+//	./scripts/mongo-replset.sh
+//	MONGO_URI="mongodb://localhost:27017/?replicaSet=rs0&directConnection=true" go test ./internal/querybuilder -run Transaction
+
+type txOrder struct {
+	mgm.DefaultModel `bson:",inline"`
+	Run              string `bson:"run"`
+}
+
+func (txOrder) CollectionName() string { return "txtest_orders" }
+
+type txItem struct {
+	mgm.DefaultModel `bson:",inline"`
+	Run              string `bson:"run"`
+}
+
+func (txItem) CollectionName() string { return "txtest_items" }
+
+var errRollback = errors.New("rollback")
+
+func TestRunInTransaction(t *testing.T) {
+	ctx := connectReplicaSet(t)
+	repo := NewBaseRepository()
+
+	saveBoth := func(ctx context.Context, run string) error {
+		if err := repo.Save(ctx, &txOrder{Run: run}); err != nil {
+			return err
+		}
+		return repo.Save(ctx, &txItem{Run: run})
+	}
+
+	tests := []struct {
+		name    string
+		fn      func(ctx context.Context) error
+		wantErr error
+		want    int64 // documents of the run in each collection
+	}{
+		{
+			name: "commit",
+			fn:   func(ctx context.Context) error { return saveBoth(ctx, "commit") },
+			want: 1,
+		},
+		{
+			name: "rollback",
+			fn: func(ctx context.Context) error {
+				if err := saveBoth(ctx, "rollback"); err != nil {
+					return err
+				}
+				return errRollback
+			},
+			wantErr: errRollback,
+		},
+		{
+			name: "nested",
+			fn: func(ctx context.Context) error {
+				if err := repo.Save(ctx, &txOrder{Run: "nested"}); err != nil {
+					return err
+				}
+				return RunInTransaction(ctx, func(ctx context.Context) error {
+					if err := repo.Save(ctx, &txItem{Run: "nested"}); err != nil {
+						return err
+					}
+					return errRollback
+				})
+			},
+			wantErr: errRollback,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := RunInTransaction(ctx, tt.fn)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RunInTransaction() error = %v, want %v", err, tt.wantErr)
+			}
+
+			for _, model := range []mgm.Model{&txOrder{}, &txItem{}} {
+				got, err := repo.CountDocuments(ctx, model, bson.M{"run": tt.name})
+				if err != nil {
+					t.Fatal(err)
+				}
+				if got != tt.want {
+					t.Errorf("%s has %d documents, want %d", mgm.CollName(model), got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+// connectReplicaSet connects mgm to MONGO_URI and creates the test
+// collections, which are dropped when the test ends. It skips the test
+// when MONGO_URI is unset or is not a replica set.
+func connectReplicaSet(t *testing.T) context.Context {
+	t.Helper()
+
+	uri := os.Getenv("MONGO_URI")
+	if uri == "" {
+		t.Skip("MONGO_URI is not set")
+	}
+	dbName := os.Getenv("MONGO_DB_NAME")
+	if dbName == "" {
+		dbName = "querybuilder_test"
+	}
+
+	ctx := context.Background()
+	if err := mgm.SetDefaultConfig(&mgm.Config{CtxTimeout: 10 * time.Second}, dbName, options.Client().ApplyURI(uri)); err != nil {
+		t.Fatal(err)
+	}
+	supported, err := SupportsTransactions(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !supported {
+		t.Skip("MONGO_URI is not a replica set")
+	}
+
+	// Collections must exist before a transaction writes to them on MongoDB < 4.4
+	_, _, db, _ := mgm.DefaultConfigs()
+	for _, name := range []string{txOrder{}.CollectionName(), txItem{}.CollectionName()} {
+		_ = db.CreateCollection(ctx, name)
+		t.Cleanup(func() { _ = db.Collection(name).Drop(context.Background()) })
+	}
+	return ctx
+}
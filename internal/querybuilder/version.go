@@ -0,0 +1,251 @@
+package querybuilder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// VersionedModel opts a model into optimistic concurrency control when
+// embedded inline:
+//
+//	type User struct {
+//		mgm.DefaultModel            `bson:",inline"`
+//		querybuilder.VersionedModel `bson:",inline"`
+//	}
+//
+// Every BaseRepository write then increments version. UpdateById and the
+// WithVersion methods only write when the stored version still matches and
+// return a *ConflictError otherwise.
+type VersionedModel struct {
+	Version int64 `json:"version" bson:"version"`
+}
+
+// GetVersion returns the version the document was read at
+func (m *VersionedModel) GetVersion() int64 {
+	return m.Version
+}
+
+func (m *VersionedModel) setVersion(version int64) {
+	m.Version = version
+}
+
+// Versioned is implemented by models embedding VersionedModel
+type Versioned interface {
+	GetVersion() int64
+	setVersion(version int64)
+}
+
+// IsVersioned reports whether model embeds VersionedModel
+func IsVersioned(model mgm.Model) bool {
+	_, ok := model.(Versioned)
+	return ok
+}
+
+// ErrConflict is matched by every *ConflictError
+var ErrConflict = errors.New("version conflict")
+
+// ConflictError reports a write made against a stale version. It matches
+// ErrConflict with errors.Is.
+type ConflictError struct {
+	Collection string
+	ID         string
+	Version    int64
+}
+
+func (e *ConflictError) Error() string {
+	if e.ID == "" {
+		return fmt.Sprintf("%s: version %d is stale", e.Collection, e.Version)
+	}
+	return fmt.Sprintf("%s: document %s changed since version %d", e.Collection, e.ID, e.Version)
+}
+
+func (e *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// IsConflict reports whether err means the document changed since it was read
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// UpdateOneWithVersion is UpdateOne for a document read at version. It
+// returns a *ConflictError when the document matching filter has moved on,
+// and mongo.ErrNoDocuments when there is none.
+func (r *BaseRepository) UpdateOneWithVersion(ctx context.Context, model mgm.Model, filter interface{}, version int64, update bson.M) (*mongo.UpdateResult, error) {
+	ctx = contextOrBackground(ctx)
+	result, err := r.UpdateOne(ctx, model, versionFilter(filter, version), update)
+	if err != nil {
+		return nil, err
+	}
+	if result.MatchedCount == 0 {
+		return nil, r.conflict(ctx, model, filter, "", version)
+	}
+	return result, nil
+}
+
+// FindOneAndUpdateWithVersion is FindOneAndUpdate for a document read at
+// version, with the same errors as UpdateOneWithVersion
+func (r *BaseRepository) FindOneAndUpdateWithVersion(ctx context.Context, model mgm.Model, filter interface{}, version int64, update bson.M, opts *options.FindOneAndUpdateOptions) error {
+	ctx = contextOrBackground(ctx)
+	err := r.FindOneAndUpdate(ctx, model, versionFilter(filter, version), update, opts)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return r.conflict(ctx, model, filter, "", version)
+	}
+	return err
+}
+
+// updateVersioned saves model like mgm's UpdateWithCtx, but only over the
+// version it was read at, and moves model to the next version. Soft-deleted
+// documents are not found, as with the other updates.
+func (r *BaseRepository) updateVersioned(ctx context.Context, model mgm.Model, versioned Versioned) error {
+	if err := beforeUpdate(ctx, model); err != nil {
+		return err
+	}
+
+	version := versioned.GetVersion()
+	versioned.setVersion(version + 1)
+	byID := bson.M{"_id": model.GetID()}
+
+	filter := scope(ctx, model, versionFilter(byID, version))
+	t := track(ctx, model, byID, false)
+	result, err := mgm.Coll(model).UpdateOne(ctx, filter, bson.M{"$set": model})
+	if err == nil && result.MatchedCount == 0 {
+		err = r.conflict(ctx, model, byID, idString(model.GetID()), version)
+	}
+	if err != nil {
+		versioned.setVersion(version)
+		return err
+	}
+	t.written(ActionUpdate, model)
+	return afterUpdate(ctx, result, model)
+}
+
+// conflict explains a versioned write that matched nothing: a
+// *ConflictError when filter still matches a document, else mongo.ErrNoDocuments
+func (r *BaseRepository) conflict(ctx context.Context, model mgm.Model, filter interface{}, id string, version int64) error {
+	count, err := mgm.Coll(model).CountDocuments(ctx, scope(ctx, model, filter), options.Count().SetLimit(1))
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return &ConflictError{Collection: mgm.CollName(model), ID: id, Version: version}
+}
+
+// versionFilter narrows filter to documents still at version. Version 0
+// also matches documents written before the model was versioned.
+func versionFilter(filter interface{}, version int64) interface{} {
+	condition := bson.D{{Key: "version", Value: version}}
+	if version == 0 {
+		condition = bson.D{{Key: "version", Value: bson.D{{Key: "$in", Value: bson.A{0, nil}}}}}
+	}
+
+	if filter == nil || isEmptyFilter(filter) {
+		return condition
+	}
+	return bson.D{{Key: "$and", Value: bson.A{filter, condition}}}
+}
+
+// bumpVersion adds {$inc: {version: 1}} to update for versioned models.
+// Updates other than bson.M and bson.D documents are returned unchanged.
+func bumpVersion(model mgm.Model, update interface{}) interface{} {
+	if !IsVersioned(model) {
+		return update
+	}
+
+	switch u := update.(type) {
+	case bson.M:
+		bumped := make(bson.M, len(u)+1)
+		for key, value := range u {
+			bumped[key] = value
+		}
+		bumped["$inc"] = withVersionIncrement(u["$inc"])
+		return bumped
+	case bson.D:
+		bumped := make(bson.D, 0, len(u)+1)
+		var inc interface{}
+		for _, elem := range u {
+			if elem.Key == "$inc" {
+				inc = elem.Value
+				continue
+			}
+			bumped = append(bumped, elem)
+		}
+		return append(bumped, bson.E{Key: "$inc", Value: withVersionIncrement(inc)})
+	}
+	return update
+}
+
+// withVersionIncrement merges the version increment into an existing $inc
+func withVersionIncrement(inc interface{}) interface{} {
+	switch fields := inc.(type) {
+	case bson.M:
+		merged := make(bson.M, len(fields)+1)
+		for key, value := range fields {
+			merged[key] = value
+		}
+		merged["version"] = 1
+		return merged
+	case bson.D:
+		merged := make(bson.D, 0, len(fields)+1)
+		merged = append(merged, fields...)
+		return append(merged, bson.E{Key: "version", Value: 1})
+	}
+	return bson.M{"version": 1}
+}
+
+// beforeUpdate runs the hooks mgm runs before an update
+func beforeUpdate(ctx context.Context, model mgm.Model) error {
+	if hook, ok := model.(mgm.UpdatingHookWithCtx); ok {
+		if err := hook.Updating(ctx); err != nil {
+			return err
+		}
+	} else if hook, ok := model.(mgm.UpdatingHook); ok {
+		if err := hook.Updating(); err != nil {
+			return err
+		}
+	}
+
+	if hook, ok := model.(mgm.SavingHookWithCtx); ok {
+		return hook.Saving(ctx)
+	} else if hook, ok := model.(mgm.SavingHook); ok {
+		return hook.Saving()
+	}
+	return nil
+}
+
+// afterUpdate runs the hooks mgm runs after an update
+func afterUpdate(ctx context.Context, result *mongo.UpdateResult, model mgm.Model) error {
+	if hook, ok := model.(mgm.UpdatedHookWithCtx); ok {
+		if err := hook.Updated(ctx, result); err != nil {
+			return err
+		}
+	} else if hook, ok := model.(mgm.UpdatedHook); ok {
+		if err := hook.Updated(result); err != nil {
+			return err
+		}
+	}
+
+	if hook, ok := model.(mgm.SavedHookWithCtx); ok {
+		return hook.Saved(ctx)
+	} else if hook, ok := model.(mgm.SavedHook); ok {
+		return hook.Saved()
+	}
+	return nil
+}
+
+// idString formats a document ID for errors
+func idString(id interface{}) string {
+	if objectID, ok := id.(primitive.ObjectID); ok {
+		return objectID.Hex()
+	}
+	return fmt.Sprint(id)
+}
@@ -0,0 +1,156 @@
+package querybuilder
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/kamva/mgm/v3"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type versionedDoc struct {
+	mgm.DefaultModel `bson:",inline"`
+	VersionedModel   `bson:",inline"`
+}
+
+type plainDoc struct {
+	mgm.DefaultModel `bson:",inline"`
+}
+
+func TestVersionFilter(t *testing.T) {
+	atVersion := func(v int64) bson.D { return bson.D{{Key: "version", Value: v}} }
+	unversioned := bson.D{{Key: "version", Value: bson.D{{Key: "$in", Value: bson.A{0, nil}}}}}
+
+	tests := []struct {
+		name    string
+		filter  interface{}
+		version int64
+		want    interface{}
+	}{
+		{name: "nil filter", filter: nil, version: 3, want: atVersion(3)},
+		{name: "empty bson.M", filter: bson.M{}, version: 3, want: atVersion(3)},
+		{name: "empty bson.D", filter: bson.D{}, version: 3, want: atVersion(3)},
+		{
+			name:    "filter is combined with $and",
+			filter:  bson.M{"_id": 1},
+			version: 3,
+			want:    bson.D{{Key: "$and", Value: bson.A{bson.M{"_id": 1}, atVersion(3)}}},
+		},
+		{name: "version 0 matches unversioned documents", filter: nil, version: 0, want: unversioned},
+		{
+			name:    "version 0 with a filter",
+			filter:  bson.D{{Key: "_id", Value: 1}},
+			version: 0,
+			want:    bson.D{{Key: "$and", Value: bson.A{bson.D{{Key: "_id", Value: 1}}, unversioned}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := versionFilter(tt.filter, tt.version); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("versionFilter() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBumpVersion(t *testing.T) {
+	set := bson.M{"name": "ada"}
+
+	tests := []struct {
+		name   string
+		model  mgm.Model
+		update interface{}
+		want   interface{}
+	}{
+		{
+			name:   "unversioned model is unchanged",
+			model:  &plainDoc{},
+			update: bson.M{"$set": set},
+			want:   bson.M{"$set": set},
+		},
+		{
+			name:   "bson.M gains $inc",
+			model:  &versionedDoc{},
+			update: bson.M{"$set": set},
+			want:   bson.M{"$set": set, "$inc": bson.M{"version": 1}},
+		},
+		{
+			name:   "bson.M $inc is merged",
+			model:  &versionedDoc{},
+			update: bson.M{"$inc": bson.M{"logins": 1}},
+			want:   bson.M{"$inc": bson.M{"logins": 1, "version": 1}},
+		},
+		{
+			name:   "bson.D gains $inc last",
+			model:  &versionedDoc{},
+			update: bson.D{{Key: "$set", Value: set}},
+			want:   bson.D{{Key: "$set", Value: set}, {Key: "$inc", Value: bson.M{"version": 1}}},
+		},
+		{
+			name:   "bson.D $inc is merged",
+			model:  &versionedDoc{},
+			update: bson.D{{Key: "$inc", Value: bson.D{{Key: "logins", Value: 1}}}, {Key: "$set", Value: set}},
+			want: bson.D{
+				{Key: "$set", Value: set},
+				{Key: "$inc", Value: bson.D{{Key: "logins", Value: 1}, {Key: "version", Value: 1}}},
+			},
+		},
+		{
+			name:   "pipeline updates are unchanged",
+			model:  &versionedDoc{},
+			update: mongo.Pipeline{{{Key: "$set", Value: set}}},
+			want:   mongo.Pipeline{{{Key: "$set", Value: set}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bumpVersion(tt.model, tt.update); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("bumpVersion() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBumpVersionKeepsUpdate(t *testing.T) {
+	inc := bson.M{"logins": 1}
+	update := bson.M{"$inc": inc}
+	bumpVersion(&versionedDoc{}, update)
+
+	if !reflect.DeepEqual(update, bson.M{"$inc": bson.M{"logins": 1}}) || len(inc) != 1 {
+		t.Errorf("bumpVersion() modified its argument: %v", update)
+	}
+}
+
+func TestConflictError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "with id",
+			err:  &ConflictError{Collection: "users", ID: "abc", Version: 2},
+			want: "users: document abc changed since version 2",
+		},
+		{
+			name: "without id",
+			err:  &ConflictError{Collection: "users", Version: 2},
+			want: "users: version 2 is stale",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.err.Error() != tt.want {
+				t.Errorf("Error() = %q, want %q", tt.err.Error(), tt.want)
+			}
+			if !IsConflict(tt.err) || !IsConflict(errors.Join(errors.New("save"), tt.err)) {
+				t.Error("IsConflict() = false, want true")
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"sync/atomic"
 	"time"
 )
 
@@ -35,8 +36,44 @@ const (
 	BgCyan    = "\033[46m"
 )
 
+// Log levels, in increasing order of severity
+const (
+	LevelDebug int32 = iota
+	LevelInfo
+	LevelWarning
+	LevelError
+)
+
+// logLevel is the minimum level that gets printed
+var logLevel atomic.Int32
+
+// SetLogLevel sets the minimum level printed: debug, info, warning or error
+func SetLogLevel(level string) error {
+	switch level {
+	case "debug":
+		logLevel.Store(LevelDebug)
+	case "info":
+		logLevel.Store(LevelInfo)
+	case "warning":
+		logLevel.Store(LevelWarning)
+	case "error":
+		logLevel.Store(LevelError)
+	default:
+		return fmt.Errorf("unknown log level %q", level)
+	}
+	return nil
+}
+
+// enabled reports whether messages at level should be printed
+func enabled(level int32) bool {
+	return level >= logLevel.Load()
+}
+
 // LogSuccess logs a success message in green
 func LogSuccess(message string) {
+	if !enabled(LevelInfo) {
+		return
+	}
 	timestamp := time.Now().Format("2006/01/02 15:04:05")
 	fmt.Printf("%s[SUCCESS]%s %s%s %s✅ %s%s\n",
 		ColorBoldGreen, ColorReset,
@@ -46,6 +83,9 @@ func LogSuccess(message string) {
 
 // LogError logs an error message in red
 func LogError(message string) {
+	if !enabled(LevelError) {
+		return
+	}
 	timestamp := time.Now().Format("2006/01/02 15:04:05")
 	fmt.Printf("%s[ERROR]%s %s%s %s❌ %s%s\n",
 		ColorBoldRed, ColorReset,
@@ -55,6 +95,9 @@ func LogError(message string) {
 
 // LogWarning logs a warning message in yellow
 func LogWarning(message string) {
+	if !enabled(LevelWarning) {
+		return
+	}
 	timestamp := time.Now().Format("2006/01/02 15:04:05")
 	fmt.Printf("%s[WARNING]%s %s%s %s⚠️  %s%s\n",
 		ColorBoldYellow, ColorReset,
@@ -64,6 +107,9 @@ func LogWarning(message string) {
 
 // LogInfo logs an info message in blue
 func LogInfo(message string) {
+	if !enabled(LevelInfo) {
+		return
+	}
 	timestamp := time.Now().Format("2006/01/02 15:04:05")
 	fmt.Printf("%s[INFO]%s %s%s %sℹ️  %s%s\n",
 		ColorBoldBlue, ColorReset,
@@ -73,6 +119,9 @@ func LogInfo(message string) {
 
 // LogDebug logs a debug message in cyan
 func LogDebug(message string) {
+	if !enabled(LevelDebug) {
+		return
+	}
 	timestamp := time.Now().Format("2006/01/02 15:04:05")
 	fmt.Printf("%s[DEBUG]%s %s%s %s🔍 %s%s\n",
 		ColorBoldCyan, ColorReset,
@@ -82,6 +131,9 @@ func LogDebug(message string) {
 
 // LogDatabase logs a database message in magenta
 func LogDatabase(message string) {
+	if !enabled(LevelInfo) {
+		return
+	}
 	timestamp := time.Now().Format("2006/01/02 15:04:05")
 	fmt.Printf("%s[DATABASE]%s %s%s %s🗄️  %s%s\n",
 		ColorBoldMagenta, ColorReset,
@@ -91,6 +143,9 @@ func LogDatabase(message string) {
 
 // LogServer logs a server message in bold blue
 func LogServer(message string) {
+	if !enabled(LevelInfo) {
+		return
+	}
 	timestamp := time.Now().Format("2006/01/02 15:04:05")
 	fmt.Printf("%s[SERVER]%s %s%s %s🚀 %s%s\n",
 		ColorBoldBlue, ColorReset,
@@ -100,6 +155,9 @@ func LogServer(message string) {
 
 // LogRequest logs an HTTP request in white
 func LogRequest(method, path, status string) {
+	if !enabled(LevelInfo) {
+		return
+	}
 	timestamp := time.Now().Format("2006/01/02 15:04:05")
 
 	// Color status code based on value
@@ -0,0 +1,10 @@
+// Package locales embeds the global locale bundles into the binary.
+package locales
+
+import "embed"
+
+// FS holds the bundles in this directory. Add a pattern here when
+// introducing YAML bundles.
+//
+//go:embed *.json
+var FS embed.FS
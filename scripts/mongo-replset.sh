@@ -0,0 +1,36 @@
+#!/bin/bash
+
+# Starts a single-node MongoDB replica set in Docker, which is enough for
+# multi-document transactions. Then use:
+#   MONGO_URI="mongodb://localhost:27017/?replicaSet=rs0&directConnection=true"
+# and run the transaction tests with: go test ./internal/querybuilder -run Transaction
+
+NAME=${MONGO_CONTAINER:-fiber-mongo-rs}
+PORT=${MONGO_PORT:-27017}
+IMAGE=${MONGO_IMAGE:-mongo:7}
+
+GREEN='\033[0;32m'
+YELLOW='\033[1;33m'
+NC='\033[0m' # No Color
+
+if [ -z "$(docker ps -q -f name=^${NAME}$)" ]; then
+    echo -e "${YELLOW}🐳 Starting ${IMAGE} as ${NAME} on port ${PORT}...${NC}"
+    docker rm -f "$NAME" >/dev/null 2>&1
+    docker run -d --name "$NAME" -p "$PORT:27017" "$IMAGE" --replSet rs0 --bind_ip_all >/dev/null || exit 1
+fi
+
+echo -e "${YELLOW}⏳ Waiting for mongod...${NC}"
+until docker exec "$NAME" mongosh --quiet --eval "db.adminCommand('ping').ok" >/dev/null 2>&1; do
+    sleep 1
+done
+
+docker exec "$NAME" mongosh --quiet --eval "
+try { rs.status() } catch (e) {
+    rs.initiate({ _id: 'rs0', members: [{ _id: 0, host: 'localhost:27017' }] })
+}" >/dev/null
+
+until [ "$(docker exec "$NAME" mongosh --quiet --eval "db.hello().isWritablePrimary")" = "true" ]; do
+    sleep 1
+done
+
+echo -e "${GREEN}✅ Replica set rs0 ready: mongodb://localhost:${PORT}/?replicaSet=rs0&directConnection=true${NC}"